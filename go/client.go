@@ -8,25 +8,40 @@ package framequery
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	defaultBaseURL      = "https://api.framequery.com/v1/api"
-	defaultPollInterval = 5 * time.Second
-	defaultTimeout      = 24 * time.Hour
-	defaultMaxRetries   = 2
-	defaultHTTPTimeout  = 5 * time.Minute
-	version             = "0.1.0"
+	defaultBaseURL          = "https://api.framequery.com/v1/api"
+	defaultPollInterval     = 5 * time.Second
+	defaultMaxPollInterval  = 30 * time.Second
+	defaultTimeout          = 24 * time.Hour
+	defaultPollReqTimeout   = 30 * time.Second
+	defaultNotFoundGrace    = 10 * time.Second
+	notFoundRetryDelay      = time.Second
+	defaultFetchConcurrency = 8
+	defaultMaxRetries       = 2
+	defaultHTTPTimeout      = 5 * time.Minute
+	version                 = "0.1.0"
 )
 
 // Client holds auth credentials and HTTP configuration for API calls.
@@ -35,6 +50,25 @@ type Client struct {
 	apiKey     string
 	httpClient *http.Client
 	maxRetries int
+	captureRaw bool
+	dropRaw    bool
+	strict     bool
+	userAgent  string // replaces the default; see WithUserAgent
+	uaPrefix   string
+	dedup      *uploadDedup
+
+	fetchConcurrency int
+	requestIDHeader  string // see WithRequestIDHeader
+
+	rates      *rateTracker
+	ledger     Ledger
+	randSource *lockedSource // retry jitter
+
+	baseClient   *http.Client // httpClient before debug logging and middleware
+	debugWriter  io.Writer
+	interceptors []Interceptor
+	pollHooks    []func(jobID string) func()
+	createHooks  []func(ctx context.Context, path string, body map[string]any) error
 }
 
 // Option is a functional option for New.
@@ -45,6 +79,11 @@ func WithBaseURL(u string) Option {
 	return func(c *Client) { c.baseURL = u }
 }
 
+// WithAPIKey replaces the API key, e.g. for a per-tenant Clone.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
 // WithHTTPClient replaces the default http.Client (5m timeout).
 func WithHTTPClient(hc *http.Client) Option {
 	return func(c *Client) { c.httpClient = hc }
@@ -60,6 +99,53 @@ func WithTimeout(d time.Duration) Option {
 	return func(c *Client) { c.httpClient.Timeout = d }
 }
 
+// WithFetchConcurrency caps parallel GetJob calls in GetJobs and RecentResults (default 8).
+func WithFetchConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.fetchConcurrency = n
+		}
+	}
+}
+
+// WithCaptureRawResponse makes GetJob keep the verbatim response body on Job.RawBody.
+func WithCaptureRawResponse(capture bool) Option {
+	return func(c *Client) { c.captureRaw = capture }
+}
+
+// WithoutRaw leaves Job.Raw and ProcessingResult.Raw nil on jobs from the
+// API, keeping only the typed fields. Raw otherwise holds a second decoded
+// copy of every payload, megabytes for long videos. Manifest overrides, which
+// are read from Raw, are lost with it.
+func WithoutRaw() Option {
+	return func(c *Client) { c.dropRaw = true }
+}
+
+// WithUserAgent replaces the User-Agent header, framequery-go/<version> by
+// default. Prefer WithUserAgentPrefix, which keeps the SDK version visible to
+// FrameQuery support.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithUserAgentPrefix puts prefix, such as "my-app/1.0", before the
+// User-Agent header: "my-app/1.0 framequery-go/<version>".
+func WithUserAgentPrefix(prefix string) Option {
+	return func(c *Client) { c.uaPrefix = prefix }
+}
+
+// userAgentHeader returns the User-Agent to send.
+func (c *Client) userAgentHeader() string {
+	ua := c.userAgent
+	if ua == "" {
+		ua = "framequery-go/" + version
+	}
+	if c.uaPrefix != "" {
+		ua = c.uaPrefix + " " + ua
+	}
+	return ua
+}
+
 // New creates a Client. Falls back to FRAMEQUERY_API_KEY env var if apiKey is empty.
 func New(apiKey string, opts ...Option) *Client {
 	if apiKey == "" {
@@ -70,95 +156,302 @@ func New(apiKey string, opts ...Option) *Client {
 		apiKey:     apiKey,
 		maxRetries: defaultMaxRetries,
 		httpClient: &http.Client{Timeout: defaultHTTPTimeout},
+
+		fetchConcurrency: defaultFetchConcurrency,
+		rates:            &rateTracker{},
+		randSource:       newLockedSource(rand.NewSource(time.Now().UnixNano())),
+	}
+	c.configure(opts)
+	return c
+}
+
+// Clone returns a copy of c with opts applied on top. The original is not
+// modified. Debug logging and middleware already on c carry over, also
+// around a transport or client that opts replace; the clone gets its own
+// rate limit state and upload dedup table.
+//
+//	tenant := base.Clone(framequery.WithAPIKey(tenantKey))
+func (c *Client) Clone(opts ...Option) *Client {
+	clone := *c
+	clone.rates = &rateTracker{}
+	if c.dedup != nil {
+		clone.dedup = newUploadDedup()
+	}
+	clone.configure(opts)
+	return &clone
+}
+
+// configure applies opts to the unwrapped http.Client, then installs debug
+// logging and middleware around its transport.
+func (c *Client) configure(opts []Option) {
+	if c.baseClient != nil {
+		hc := *c.baseClient
+		c.httpClient = &hc
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
-	return c
+	c.baseClient = c.httpClient
+	if c.debugWriter != nil {
+		c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return &debugTransport{next: next, w: c.debugWriter}
+		})
+	}
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		ic := c.interceptors[i]
+		c.wrapTransport(func(next http.RoundTripper) http.RoundTripper {
+			return &interceptorTransport{interceptor: ic, next: next}
+		})
+	}
+}
+
+// wrapTransport installs a RoundTripper around the current transport. The
+// http.Client is copied so one passed to WithHTTPClient is not modified.
+func (c *Client) wrapTransport(wrap func(http.RoundTripper) http.RoundTripper) {
+	hc := *c.httpClient
+	next := hc.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	hc.Transport = wrap(next)
+	c.httpClient = &hc
 }
 
 // Process uploads a video file from disk and blocks until the job finishes or fails.
 func (c *Client) Process(ctx context.Context, path string, opts *ProcessOptions) (*ProcessingResult, error) {
-	var uploadOpts *UploadOptions
-	if opts != nil {
-		uploadOpts = &UploadOptions{
-			CallbackURL:    opts.CallbackURL,
-			ProcessingMode: opts.ProcessingMode,
-			IdempotencyKey: opts.IdempotencyKey,
-			AudioTracks:    opts.AudioTracks,
-		}
-	}
-	job, err := c.Upload(ctx, path, uploadOpts)
+	job, err := c.Upload(ctx, path, opts.uploadOptions())
 	if err != nil {
 		return nil, err
 	}
-	return c.poll(ctx, job.ID, opts)
+	return c.waitForUpload(ctx, job, opts)
 }
 
 // ProcessURL submits a remote video URL and blocks until the job finishes or fails.
 func (c *Client) ProcessURL(ctx context.Context, videoURL string, opts *ProcessOptions) (*ProcessingResult, error) {
+	job, err := c.SubmitURL(ctx, videoURL, opts.uploadOptions())
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.waitForJob(ctx, job.ID, opts, true)
+	if result == nil {
+		return nil, err
+	}
+	result.Manifest = job.Manifest.withOverrides(result.Raw)
+	return result, err // err is nil or ErrPartialResult
+}
+
+// SubmitURL creates a job from a remote video URL and returns it without
+// waiting for processing; the URL counterpart of Upload. opts.Filename and
+// the chunking fields are ignored.
+func (c *Client) SubmitURL(ctx context.Context, videoURL string, opts *UploadOptions) (*Job, error) {
 	body := map[string]interface{}{"url": videoURL}
-	if opts != nil {
-		if opts.CallbackURL != "" {
-			body["callbackUrl"] = opts.CallbackURL
-		}
-		if opts.ProcessingMode != "" {
-			body["processingMode"] = opts.ProcessingMode
-		}
-		if opts.IdempotencyKey != "" {
-			body["idempotencyKey"] = opts.IdempotencyKey
-		}
-		if len(opts.AudioTracks) > 0 {
-			body["audioTracks"] = opts.AudioTracks
-		}
+	if err := applyCreateOptions(body, opts); err != nil {
+		return nil, err
+	}
+	if err := c.runCreateHooks(ctx, "/jobs/from-url", body); err != nil {
+		return nil, err
 	}
+	manifest := newManifest(c.baseURL, body)
 	var resp createJobFromURLResponse
 	if err := c.doJSON(ctx, http.MethodPost, "/jobs/from-url", body, &resp); err != nil {
 		return nil, err
 	}
-	return c.poll(ctx, resp.JobID, opts)
+	if opts != nil {
+		newEventWriter(opts.EventWriter).emit(ProgressEvent{Type: EventJobCreated, JobID: resp.JobID})
+	}
+	status := resp.Status
+	if status == "" {
+		status = StatusPendingFetch
+	}
+	return &Job{
+		ID:       resp.JobID,
+		Status:   status,
+		Manifest: manifest,
+		Raw:      map[string]any{"jobId": resp.JobID, "status": status},
+	}, nil
 }
 
 // Upload sends a video file and returns the Job without waiting for processing.
+// With WithUploadDedup, concurrent calls for the same file share one job.
 func (c *Client) Upload(ctx context.Context, path string, opts *UploadOptions) (*Job, error) {
-	filename := filepath.Base(path)
+	if c.dedup == nil {
+		job, err := c.upload(ctx, path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return job, nil
+	}
+	key, err := dedupKey(path)
+	if err != nil {
+		return nil, fmt.Errorf("framequery: open file: %w", err)
+	}
+	entry, leader := c.dedup.acquire(key)
+	if !leader {
+		return entry.wait(ctx)
+	}
+	job, err := c.upload(ctx, path, opts)
+	if err != nil {
+		job = nil
+	}
+	if entry != nil {
+		c.dedup.finish(key, entry, job, err)
+	}
+	return job, err
+}
+
+// upload opens path and uploads it. If sending fails, the created job is
+// returned along with the error.
+func (c *Client) upload(ctx context.Context, path string, opts *UploadOptions) (*Job, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("framequery: open file: %w", err)
+	}
+	defer f.Close()
+	return c.uploadFile(ctx, f, opts)
+}
+
+// UploadFile is Upload for an already-open file, e.g. one handed over by
+// another subsystem. The whole file is sent regardless of its offset, and the
+// filename defaults to the base of f.Name(). Non-regular files such as pipes
+// are spooled to a temporary file first. The caller still owns f.
+func (c *Client) UploadFile(ctx context.Context, f *os.File, opts *UploadOptions) (*Job, error) {
+	job, err := c.uploadFile(ctx, f, opts)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// UploadFS is Upload for a file in fsys, so callers sandboxed behind an fs.FS
+// don't need a real path. The filename defaults to the base of name.
+func (c *Client) UploadFS(ctx context.Context, fsys fs.FS, name string, opts *UploadOptions) (*Job, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("framequery: open file: %w", err)
+	}
+	defer f.Close()
+	src, size := newUploadSource(f)
+	job, err := c.uploadFrom(ctx, src, size, path.Base(name), opts)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// ProcessFile is Process for an already-open file; see UploadFile.
+func (c *Client) ProcessFile(ctx context.Context, f *os.File, opts *ProcessOptions) (*ProcessingResult, error) {
+	job, err := c.UploadFile(ctx, f, opts.uploadOptions())
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForUpload(ctx, job, opts)
+}
+
+// ProcessFS is Process for a file in fsys; see UploadFS.
+func (c *Client) ProcessFS(ctx context.Context, fsys fs.FS, name string, opts *ProcessOptions) (*ProcessingResult, error) {
+	job, err := c.UploadFS(ctx, fsys, name, opts.uploadOptions())
+	if err != nil {
+		return nil, err
+	}
+	return c.waitForUpload(ctx, job, opts)
+}
+
+func (c *Client) uploadFile(ctx context.Context, f *os.File, opts *UploadOptions) (*Job, error) {
+	src, size := newUploadSource(f)
+	return c.uploadFrom(ctx, src, size, filepath.Base(f.Name()), opts)
+}
+
+// uploadFrom creates a job and sends src to it. size is -1 if unknown. If
+// sending fails, the created job is returned along with the error.
+func (c *Client) uploadFrom(ctx context.Context, src io.Reader, size int64, filename string, opts *UploadOptions) (*Job, error) {
 	if opts != nil && opts.Filename != "" {
 		filename = opts.Filename
 	}
 
 	// Create job
 	body := map[string]interface{}{"fileName": filename}
+	if err := applyCreateOptions(body, opts); err != nil {
+		return nil, err
+	}
+	if err := c.runCreateHooks(ctx, "/jobs", body); err != nil {
+		return nil, err
+	}
+	var events *eventWriter
 	if opts != nil {
-		if opts.CallbackURL != "" {
-			body["callbackUrl"] = opts.CallbackURL
-		}
-		if opts.ProcessingMode != "" {
-			body["processingMode"] = opts.ProcessingMode
-		}
-		if opts.IdempotencyKey != "" {
-			body["idempotencyKey"] = opts.IdempotencyKey
-		}
-		if len(opts.AudioTracks) > 0 {
-			body["audioTracks"] = opts.AudioTracks
-		}
+		events = newEventWriter(opts.EventWriter)
 	}
 	var resp createJobResponse
 	if err := c.doJSON(ctx, http.MethodPost, "/jobs", body, &resp); err != nil {
 		return nil, err
 	}
+	events.emit(ProgressEvent{Type: EventJobCreated, JobID: resp.JobID})
 
-	// Upload file to signed URL
-	f, err := os.Open(path)
+	job := newUploadedJob(resp.JobID, filename, newManifest(c.baseURL, body))
+	stats, err := c.sendFile(ctx, &resp, src, size, filename, opts, events)
+	job.UploadStats = stats
 	if err != nil {
-		return nil, fmt.Errorf("framequery: open file: %w", err)
+		return job, err
 	}
-	defer f.Close()
+	return job, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, resp.UploadURL, f)
+// waitForUpload polls a just-uploaded job to completion, as Process does.
+func (c *Client) waitForUpload(ctx context.Context, job *Job, opts *ProcessOptions) (*ProcessingResult, error) {
+	result, err := c.waitForJob(ctx, job.ID, opts, true)
+	if result == nil {
+		return nil, err
+	}
+	result.Manifest = job.Manifest.withOverrides(result.Raw)
+	return result, err // err is nil or ErrPartialResult
+}
+
+// sendFile uploads src to the job's signed upload target. size is -1 if
+// unknown, in which case src is spooled to a temporary file first.
+func (c *Client) sendFile(ctx context.Context, resp *createJobResponse, src io.Reader, size int64, filename string, opts *UploadOptions, events *eventWriter) (*UploadStats, error) {
+	chunkSize := int64(defaultChunkSize)
+	if opts != nil && opts.ChunkSize > 0 {
+		chunkSize = opts.ChunkSize
+	}
+	forceParts := opts != nil && opts.UseMultipart
+	chunked := forceParts || (resp.MultipartUpload && size > chunkSize)
+	resumable := opts != nil && opts.Resumable && isPutUpload(resp.UploadMethod)
+	ra, seekable := src.(io.ReaderAt)
+	if size < 0 || ((chunked || resumable) && !seekable) {
+		tmp, n, err := spool(src)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		src, ra, size = tmp, tmp, n
+		chunked = forceParts || (resp.MultipartUpload && size > chunkSize)
+	}
+	if size > 0 && chunked {
+		concurrency := defaultChunkConcurrency
+		if opts != nil && opts.ChunkConcurrency > 0 {
+			concurrency = opts.ChunkConcurrency
+		}
+		err := c.uploadMultipart(ctx, resp.JobID, ra, size, chunkSize, concurrency, events)
+		if !errors.Is(err, errMultipartUnavailable) {
+			return nil, err
+		}
+		// Parts were read by offset, so src is still at the start
+	}
+	if size > 0 && resumable {
+		return c.putResumable(ctx, resp.UploadURL, ra, size, resp.JobID, events)
+	}
+
+	if events != nil {
+		src = &progressReader{r: src, events: events, jobID: resp.JobID, total: size}
+	}
+
+	req, err := newUploadRequest(ctx, resp, src, size, filename)
 	if err != nil {
+		if _, ok := err.(*UnsupportedUploadMethodError); ok {
+			return nil, err
+		}
 		return nil, fmt.Errorf("framequery: create upload request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/octet-stream")
 
 	uploadResp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -171,62 +464,716 @@ func (c *Client) Upload(ctx context.Context, path string, opts *UploadOptions) (
 		return nil, fmt.Errorf("framequery: upload failed %s: %s", uploadResp.Status, string(b))
 	}
 
+	return nil, nil
+}
+
+// newUploadedJob is the Job returned once a file has been sent.
+func newUploadedJob(jobID, filename string, manifest *ProcessingManifest) *Job {
 	return &Job{
-		ID:       resp.JobID,
-		Status:   "PENDING_UPLOAD",
+		ID:       jobID,
+		Status:   StatusPendingUpload,
 		Filename: filename,
-		Raw:      map[string]any{"jobId": resp.JobID, "status": "PENDING_UPLOAD"},
-	}, nil
+		Manifest: manifest,
+		Raw:      map[string]any{"jobId": jobID, "status": StatusPendingUpload},
+	}
+}
+
+// GetUploadURL returns a fresh signed upload target for a job still in
+// PENDING_UPLOAD, e.g. after a crash between creating the job and uploading.
+func (c *Client) GetUploadURL(ctx context.Context, jobID string) (*UploadTarget, error) {
+	var resp createJobResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"/upload-url", nil, &resp); err != nil {
+		return nil, err
+	}
+	t := &UploadTarget{
+		URL:        resp.UploadURL,
+		Method:     resp.UploadMethod,
+		FieldName:  resp.UploadFieldName,
+		FormFields: resp.UploadFields,
+	}
+	if t.Method == "" {
+		t.Method = http.MethodPut
+	}
+	if resp.ExpiresIn > 0 {
+		t.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return t, nil
+}
+
+// UploadToJob uploads a file to an existing PENDING_UPLOAD job using a fresh
+// signed URL. Only the filename, event, and chunking fields of opts apply. If
+// the job already has its file the API returns 409; check IsConflictError.
+func (c *Client) UploadToJob(ctx context.Context, jobID, path string, opts *UploadOptions) (*Job, error) {
+	filename := filepath.Base(path)
+	if opts != nil && opts.Filename != "" {
+		filename = opts.Filename
+	}
+	target, err := c.GetUploadURL(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &createJobResponse{
+		JobID:           jobID,
+		UploadURL:       target.URL,
+		UploadMethod:    target.Method,
+		UploadFieldName: target.FieldName,
+		UploadFields:    target.FormFields,
+	}
+	var events *eventWriter
+	if opts != nil {
+		events = newEventWriter(opts.EventWriter)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("framequery: open file: %w", err)
+	}
+	defer f.Close()
+	src, size := newUploadSource(f)
+	stats, err := c.sendFile(ctx, resp, src, size, filename, opts, events)
+	if err != nil {
+		return nil, err
+	}
+	job := newUploadedJob(jobID, filename, nil)
+	job.UploadStats = stats
+	return job, nil
 }
 
 // GetJob returns a job's current status and results.
+// With WithCaptureRawResponse, Job.RawBody holds the verbatim response.
 func (c *Client) GetJob(ctx context.Context, jobID string) (*Job, error) {
-	var raw map[string]any
-	if err := c.doJSON(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID), nil, &raw); err != nil {
+	return c.getJob(ctx, jobID, c.captureRaw)
+}
+
+// GetJobs fetches several jobs in parallel. The returned slice lines up with
+// jobIDs. If any fetch fails, the error is a *GetJobsError whose Errors also
+// line up with jobIDs; the jobs that did load are still returned.
+func (c *Client) GetJobs(ctx context.Context, jobIDs []string) ([]Job, error) {
+	jobs := make([]Job, len(jobIDs))
+	errs := make([]error, len(jobIDs))
+	sem := make(chan struct{}, c.fetchConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range jobIDs {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			job, err := c.GetJob(ctx, id)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			jobs[i] = *job
+		}(i, id)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return jobs, &GetJobsError{Errors: errs}
+		}
+	}
+	return jobs, nil
+}
+
+// GetJobResult is GetResult.
+func (c *Client) GetJobResult(ctx context.Context, jobID string) (*ProcessingResult, error) {
+	return c.GetResult(ctx, jobID)
+}
+
+// GetResult fetches a job and returns its parsed result without polling.
+// Returns a *JobNotCompleteError if the job is still running, or the job's
+// failure error if it failed, was cancelled, or expired.
+func (c *Client) GetResult(ctx context.Context, jobID string) (*ProcessingResult, error) {
+	job, err := c.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := resultError(job); err != nil {
+		return nil, err
+	}
+	return job.result(), nil
+}
+
+// CancelJob stops a queued or running job and returns it in the CANCELLED state.
+// Cancelling a job that already finished returns a 409; check with IsConflictError.
+func (c *Client) CancelJob(ctx context.Context, jobID string) (*Job, error) {
+	var raw json.RawMessage
+	if err := c.doJSON(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID)+"/cancel", nil, &raw); err != nil {
+		return nil, err
+	}
+	job, err := c.decodeJobBody(raw)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == "" {
+		job.ID = jobID
+	}
+	if job.Status == "" {
+		job.Status = StatusCancelled
+	}
+	return job, nil
+}
+
+// RetryJob re-runs processing on a job's already-uploaded source, typically
+// after a transient failure. opts may override CallbackURL, ProcessingMode,
+// and Features; polling fields are ignored. Returns the queued job. If the
+// source has been purged the API returns 410; check errors.Is(err, ErrSourcePurged).
+func (c *Client) RetryJob(ctx context.Context, jobID string, opts *ProcessOptions) (*Job, error) {
+	body := map[string]interface{}{}
+	if opts != nil {
+		if opts.CallbackURL != "" {
+			body["callbackUrl"] = opts.CallbackURL
+		}
+		if opts.ProcessingMode != "" {
+			body["processingMode"] = opts.ProcessingMode
+		}
+		if len(opts.Features) > 0 {
+			body["features"] = opts.Features
+		}
+	}
+	var raw json.RawMessage
+	if err := c.doJSON(ctx, http.MethodPost, "/jobs/"+url.PathEscape(jobID)+"/retry", body, &raw); err != nil {
+		return nil, err
+	}
+	job, err := c.decodeJobBody(raw)
+	if err != nil {
+		return nil, err
+	}
+	if job.ID == "" {
+		job.ID = jobID
+	}
+	return job, nil
+}
+
+// RetryAndWait calls RetryJob and blocks until the re-run finishes or fails.
+func (c *Client) RetryAndWait(ctx context.Context, jobID string, opts *ProcessOptions) (*ProcessingResult, error) {
+	job, err := c.RetryJob(ctx, jobID, opts)
+	if err != nil {
 		return nil, err
 	}
-	return parseJob(raw), nil
+	return c.waitForJob(ctx, job.ID, opts, true)
+}
+
+// UpdateJob changes a job's display name, metadata, or tags. Fields left
+// empty in patch are unchanged. A concurrent update surfaces as a 409; check
+// errors.Is(err, ErrConflict).
+func (c *Client) UpdateJob(ctx context.Context, jobID string, patch *JobPatch) (*Job, error) {
+	var raw json.RawMessage
+	if err := c.doJSON(ctx, http.MethodPatch, "/jobs/"+url.PathEscape(jobID), patch, &raw); err != nil {
+		return nil, err
+	}
+	return c.decodeJobBody(raw)
+}
+
+// CloneJob creates a new job from an existing job's stored source, so old
+// videos can be re-run with different options without re-uploading. Fields
+// left empty in opts are carried over from the original. Returns the new,
+// queued job. If the source has expired, errors.Is(err, ErrSourceExpired).
+func (c *Client) CloneJob(ctx context.Context, jobID string, opts *CloneOptions) (*Job, error) {
+	body := map[string]interface{}{}
+	if opts != nil {
+		if err := applyCreateOptions(body, &UploadOptions{
+			CallbackURL:    opts.CallbackURL,
+			ProcessingMode: opts.ProcessingMode,
+			Features:       opts.Features,
+			Metadata:       opts.Metadata,
+			Tags:           opts.Tags,
+		}); err != nil {
+			return nil, err
+		}
+		if opts.DisplayName != "" {
+			body["displayName"] = opts.DisplayName
+		}
+	}
+	clonePath := "/jobs/" + url.PathEscape(jobID) + "/clone"
+	if err := c.runCreateHooks(ctx, clonePath, body); err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := c.doJSON(ctx, http.MethodPost, clonePath, body, &raw); err != nil {
+		return nil, err
+	}
+	job, err := c.decodeJobBody(raw)
+	if err != nil {
+		return nil, err
+	}
+	job.Manifest = newManifest(c.baseURL, body)
+	return job, nil
+}
+
+// CloneAndWait calls CloneJob and blocks until the new job finishes or fails.
+// waitOpts tunes polling only.
+func (c *Client) CloneAndWait(ctx context.Context, jobID string, opts *CloneOptions, waitOpts *ProcessOptions) (*ProcessingResult, error) {
+	job, err := c.CloneJob(ctx, jobID, opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.waitForJob(ctx, job.ID, waitOpts, true)
+	if result == nil {
+		return nil, err
+	}
+	result.Manifest = job.Manifest.withOverrides(result.Raw)
+	return result, err // err is nil or ErrPartialResult
+}
+
+// DeleteJob removes a job along with its uploaded source and results.
+// Deleting a job that no longer exists returns an error for which IsNotFoundError is true.
+func (c *Client) DeleteJob(ctx context.Context, jobID string) error {
+	_, err := c.doRaw(ctx, http.MethodDelete, "/jobs/"+url.PathEscape(jobID), nil)
+	return err
+}
+
+// DeleteJobs deletes every job matching opts and returns how many were
+// deleted. The filters are sent to ListJobs, and each listed job is checked
+// against them again so a server that ignores one can't widen the deletion.
+// Matching jobs are listed in full before any are deleted so deletion doesn't
+// disturb pagination. Jobs already gone (404) are skipped;
+// an auth or permission error stops the run. With DryRun, returns the number
+// of matches without deleting.
+func (c *Client) DeleteJobs(ctx context.Context, opts *DeleteJobsOptions) (int, error) {
+	if opts == nil {
+		opts = &DeleteJobsOptions{}
+	}
+	var ids []string
+	listOpts := &ListJobsOptions{Limit: MaxListJobsLimit, Status: opts.Status, Tag: opts.Tag, CreatedBefore: opts.CreatedBefore}
+	for {
+		page, err := c.ListJobs(ctx, listOpts)
+		if err != nil {
+			return 0, err
+		}
+		for i := range page.Jobs {
+			if opts.matches(&page.Jobs[i]) {
+				ids = append(ids, page.Jobs[i].ID)
+			}
+		}
+		if !page.HasMore() {
+			break
+		}
+		listOpts.Cursor = page.NextCursor
+	}
+	if opts.DryRun {
+		return len(ids), nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		deleted  int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, c.fetchConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			err := c.DeleteJob(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				deleted++
+			case IsNotFoundError(err):
+			default:
+				if firstErr == nil {
+					firstErr = err
+				}
+				if IsAuthError(err) || IsPermissionError(err) {
+					cancel()
+				}
+			}
+		}(id)
+	}
+	wg.Wait()
+	return deleted, firstErr
+}
+
+// ListJobs returns a page of jobs. Supports cursor pagination and status filtering.
+//
+// With FilenameContains set, ListJobs filters each page itself in case the
+// server ignores the parameter, and keeps fetching until it has Limit matches
+// (at least one if Limit is 0), runs out of pages, or has scanned
+// maxFilterPages pages. In the last case the page has Truncated set and
+// NextCursor resumes the scan. The page may hold more than Limit jobs.
+func (c *Client) ListJobs(ctx context.Context, opts *ListJobsOptions) (*JobPage, error) {
+	limit := 0
+	if opts != nil {
+		limit = opts.Limit
+		if limit > MaxListJobsLimit {
+			if !opts.ClampLimit {
+				return nil, fmt.Errorf("framequery: limit %d exceeds maximum of %d (set ClampLimit to clamp)", limit, MaxListJobsLimit)
+			}
+			limit = MaxListJobsLimit
+		}
+	}
+	page, err := c.fetchJobsPage(ctx, opts, limit, "")
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.FilenameContains == "" {
+		return page, nil
+	}
+
+	want := limit
+	if want == 0 {
+		want = 1
+	}
+	page.filterFilename(opts.FilenameContains)
+	for scanned := 1; len(page.Jobs) < want && page.HasMore(); scanned++ {
+		if scanned == maxFilterPages {
+			page.Truncated = true
+			break
+		}
+		next, err := c.fetchJobsPage(ctx, opts, limit, page.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+		next.filterFilename(opts.FilenameContains)
+		page.Jobs = append(page.Jobs, next.Jobs...)
+		page.DecodeErrors = append(page.DecodeErrors, next.DecodeErrors...)
+		page.NextCursor = next.NextCursor
+		if next.ServerLimit > 0 {
+			page.ServerLimit = next.ServerLimit
+		}
+	}
+	return page, nil
+}
+
+// CountJobs returns how many jobs match opts, as reported by the server.
+// Cursor and Limit in opts are ignored. With several statuses, the per-status
+// counts are summed.
+func (c *Client) CountJobs(ctx context.Context, opts *ListJobsOptions) (int, error) {
+	var o ListJobsOptions
+	if opts != nil {
+		o = *opts
+	}
+	o.Cursor = ""
+	o.Limit = 1
+	statuses := o.statusSet()
+	if len(statuses) == 0 {
+		statuses = []JobStatus{""}
+	}
+	total := 0
+	for _, st := range statuses {
+		o.Status, o.Statuses = st, nil
+		page, err := c.listJobsPage(ctx, &o, 1, "")
+		if err != nil {
+			return 0, err
+		}
+		if page.TotalCount == 0 && len(page.Jobs) > 0 {
+			return 0, errors.New("framequery: list response has no total count")
+		}
+		total += page.TotalCount
+	}
+	return total, nil
+}
+
+// CountJobsByStatus returns the number of jobs in each status. It uses the
+// server's /jobs/counts aggregate if available; otherwise it counts every
+// known status, plus any others seen on the newest page of jobs, in parallel
+// up to the fetch concurrency. Statuses with no jobs are omitted.
+func (c *Client) CountJobsByStatus(ctx context.Context) (map[JobStatus]int, error) {
+	raw, err := c.doJSONRaw(ctx, http.MethodGet, "/jobs/counts", nil)
+	if err == nil {
+		return parseStatusCounts(raw)
+	}
+	if !IsNotFoundError(err) {
+		return nil, err
+	}
+
+	statuses := make([]JobStatus, 0, len(jobStates))
+	for s := range jobStates {
+		statuses = append(statuses, s)
+	}
+	recent, err := c.listJobsPage(ctx, &ListJobsOptions{}, MaxListJobsLimit, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range recent.Jobs {
+		if j.Status != "" && !slices.Contains(statuses, j.Status) {
+			statuses = append(statuses, j.Status)
+		}
+	}
+
+	counts := make([]int, len(statuses))
+	errs := make([]error, len(statuses))
+	sem := make(chan struct{}, c.fetchConcurrency)
+	var wg sync.WaitGroup
+	for i, st := range statuses {
+		wg.Add(1)
+		go func(i int, st JobStatus) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			counts[i], errs[i] = c.CountJobs(ctx, &ListJobsOptions{Status: st})
+		}(i, st)
+	}
+	wg.Wait()
+
+	out := make(map[JobStatus]int)
+	for i, st := range statuses {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("framequery: count %s jobs: %w", st, errs[i])
+		}
+		if counts[i] > 0 {
+			out[st] = counts[i]
+		}
+	}
+	return out, nil
+}
+
+// parseStatusCounts reads a /jobs/counts response: a status-to-count object,
+// optionally under "data" and then "counts".
+func parseStatusCounts(raw map[string]any) (map[JobStatus]int, error) {
+	src := raw
+	if d, ok := src["data"].(map[string]any); ok {
+		src = d
+	}
+	if cs, ok := src["counts"].(map[string]any); ok {
+		src = cs
+	}
+	out := make(map[JobStatus]int)
+	for st, v := range src {
+		n, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("framequery: job count for %s is %T, not a number", st, v)
+		}
+		if n > 0 {
+			out[JobStatus(st)] = int(n)
+		}
+	}
+	return out, nil
+}
+
+// listResponse is a page of the job listing. The total count and page size
+// are either top level or under "meta".
+type listResponse struct {
+	Data       json.RawMessage `json:"data"`
+	NextCursor string          `json:"nextCursor"`
+	listMeta
+	Meta *listMeta `json:"meta"`
+}
+
+type listMeta struct {
+	Total      *float64 `json:"total"`
+	TotalCount *float64 `json:"totalCount"`
+	Limit      *float64 `json:"limit"`
+}
+
+// meta returns the total count and page size; missing values are 0.
+func (r *listResponse) meta() (total, limit int) {
+	m := &r.listMeta
+	if r.Meta != nil {
+		m = r.Meta
+	}
+	switch {
+	case m.Total != nil:
+		total = int(*m.Total)
+	case m.TotalCount != nil:
+		total = int(*m.TotalCount)
+	}
+	if m.Limit != nil {
+		limit = int(*m.Limit)
+	}
+	return total, limit
+}
+
+// fetchJobsPage fetches one page, merging per-status listings when opts asks
+// for more than one status.
+func (c *Client) fetchJobsPage(ctx context.Context, opts *ListJobsOptions, limit int, cursor string) (*JobPage, error) {
+	if statuses := opts.statusSet(); len(statuses) > 1 {
+		if !opts.newestFirst() {
+			return nil, fmt.Errorf("framequery: sorting by %s %s is not supported with several statuses", opts.SortBy, opts.SortOrder)
+		}
+		return c.listJobsMerged(ctx, opts, statuses, limit, cursor)
+	}
+	return c.listJobsPage(ctx, opts, limit, cursor)
 }
 
-// ListJobs returns a page of jobs. Supports cursor pagination and status filtering.
-func (c *Client) ListJobs(ctx context.Context, opts *ListJobsOptions) (*JobPage, error) {
+// listJobsPage fetches one page. A non-empty cursor overrides opts.Cursor.
+func (c *Client) listJobsPage(ctx context.Context, opts *ListJobsOptions, limit int, cursor string) (*JobPage, error) {
 	path := "/jobs"
 	params := url.Values{}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
 	if opts != nil {
-		if opts.Limit > 0 {
-			params.Set("limit", strconv.Itoa(opts.Limit))
+		if cursor == "" {
+			cursor = opts.Cursor
+		}
+		if statuses := opts.statusSet(); len(statuses) == 1 {
+			params.Set("status", string(statuses[0]))
+		}
+		if opts.Tag != "" {
+			params.Set("tag", opts.Tag)
 		}
-		if opts.Cursor != "" {
-			params.Set("cursor", opts.Cursor)
+		if !opts.CreatedAfter.IsZero() {
+			params.Set("createdAfter", opts.CreatedAfter.UTC().Format(time.RFC3339))
 		}
-		if opts.Status != "" {
-			params.Set("status", opts.Status)
+		if !opts.CreatedBefore.IsZero() {
+			params.Set("createdBefore", opts.CreatedBefore.UTC().Format(time.RFC3339))
 		}
+		if opts.FilenameContains != "" {
+			params.Set("filenameContains", opts.FilenameContains)
+		}
+		if opts.SortBy != "" {
+			params.Set("sortBy", opts.SortBy)
+		}
+		if opts.SortOrder != "" {
+			params.Set("sortOrder", opts.SortOrder)
+		}
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
 	}
 	if len(params) > 0 {
 		path += "?" + params.Encode()
 	}
 
-	// List returns {"data": [...], "nextCursor": "..."} so we need raw response
-	raw, err := c.doJSONRaw(ctx, http.MethodGet, path, nil)
+	respBody, err := c.doRaw(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
+	var resp listResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("framequery: unmarshal response: %w", err)
+	}
+	var items []json.RawMessage
+	if len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, &items); err != nil {
+			return nil, fmt.Errorf("framequery: list data: %w", err)
+		}
+	}
 
-	page := &JobPage{}
-	if cursor, ok := raw["nextCursor"].(string); ok {
-		page.NextCursor = cursor
+	page := &JobPage{RequestedLimit: limit, client: c}
+	if opts != nil {
+		page.opts = *opts
 	}
-	if items, ok := raw["data"].([]any); ok {
-		for _, item := range items {
-			if m, ok := item.(map[string]any); ok {
-				page.Jobs = append(page.Jobs, *parseJob(m))
+	page.NextCursor = resp.NextCursor
+	page.TotalCount, page.Limit = resp.meta()
+	collect := opts != nil && opts.Strict
+	for i, item := range items {
+		job, err := decodeJobRaw(item, !c.dropRaw)
+		switch {
+		case err != nil:
+		case c.strict:
+			err = checkJobShape(item)
+		case collect:
+			err = checkJobTypes(item)
+		}
+		if err != nil {
+			var v any
+			_ = json.Unmarshal(item, &v)
+			de := &DecodeError{Index: i, Item: v, Err: err}
+			if c.strict {
+				return nil, de
 			}
+			if collect {
+				page.DecodeErrors = append(page.DecodeErrors, de)
+			}
+			continue
 		}
+		page.Jobs = append(page.Jobs, *job)
+	}
+	// A short page with more to come means the server capped the page size
+	if limit > 0 && len(items) < limit && page.NextCursor != "" {
+		page.ServerLimit = len(items)
+	}
+	if opts != nil && (!opts.CreatedAfter.IsZero() || !opts.CreatedBefore.IsZero()) {
+		page.filterCreated(opts)
 	}
 	return page, nil
 }
 
+// RecentResults returns results for the n most recent completed jobs, in
+// listing order. opts may narrow the listing; with no Status filter, jobs that
+// aren't IsComplete are skipped.
+// A job whose result can't be fetched still gets an entry, with Err set.
+func (c *Client) RecentResults(ctx context.Context, n int, opts *ListJobsOptions) ([]*ProcessingResult, error) {
+	listOpts := ListJobsOptions{}
+	if opts != nil {
+		listOpts = *opts
+	}
+	// The API filters on one status, and two mean complete
+	completeOnly := listOpts.Status == ""
+
+	var jobs []Job
+	for len(jobs) < n {
+		listOpts.Limit = n - len(jobs)
+		if completeOnly || listOpts.Limit > MaxListJobsLimit {
+			listOpts.Limit = MaxListJobsLimit
+		}
+		page, err := c.ListJobs(ctx, &listOpts)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range page.Jobs {
+			if !completeOnly || j.IsComplete() {
+				jobs = append(jobs, j)
+			}
+		}
+		if !page.HasMore() {
+			break
+		}
+		listOpts.Cursor = page.NextCursor
+	}
+	if len(jobs) > n {
+		jobs = jobs[:n]
+	}
+
+	results := make([]*ProcessingResult, len(jobs))
+	sem := make(chan struct{}, c.fetchConcurrency)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		if r, ok := jobs[i].Result(); ok {
+			results[i] = r
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			job, err := c.GetJob(ctx, jobs[i].ID)
+			if err == nil {
+				if r, ok := job.Result(); ok {
+					results[i] = r
+					return
+				}
+				err = fmt.Errorf("framequery: job %s has no result (status %s)", job.ID, job.Status)
+			}
+			results[i] = &ProcessingResult{
+				JobID:     jobs[i].ID,
+				Status:    jobs[i].Status,
+				Filename:  jobs[i].Filename,
+				CreatedAt: jobs[i].CreatedAt,
+				Raw:       jobs[i].Raw,
+				Err:       err,
+			}
+		}(i)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// SearchJobsByTag returns a page of jobs carrying tag. opts may set paging and status.
+func (c *Client) SearchJobsByTag(ctx context.Context, tag string, opts *ListJobsOptions) (*JobPage, error) {
+	listOpts := ListJobsOptions{}
+	if opts != nil {
+		listOpts = *opts
+	}
+	listOpts.Tag = tag
+	return c.ListJobs(ctx, &listOpts)
+}
+
 // GetQuota returns included hours, credit balance, and plan info.
 func (c *Client) GetQuota(ctx context.Context) (*Quota, error) {
 	var q Quota
@@ -236,6 +1183,36 @@ func (c *Client) GetQuota(ctx context.Context) (*Quota, error) {
 	return &q, nil
 }
 
+// Ping checks that the API is reachable and the key is valid, using the
+// cheap quota endpoint. IsAuthError on the result means a bad key.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.GetQuota(ctx)
+	return err
+}
+
+// ValidateAPIKey checks the client's API key without submitting a job. It
+// returns:
+//   - an error matching ErrInvalidAPIKey if the key is empty, mistyped, or
+//     revoked, and one also matching ErrAPIKeyExpired if the API says the
+//     key has expired
+//   - the request's error unchanged for anything else, such as a network
+//     failure or a 5xx, where the key may well be fine
+//   - nil if the API accepted the key
+func (c *Client) ValidateAPIKey(ctx context.Context) error {
+	if c.apiKey == "" {
+		return fmt.Errorf("%w: key is empty", ErrInvalidAPIKey)
+	}
+	err := c.Ping(ctx)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return err
+	}
+	if apiErr.expiredKey() {
+		return fmt.Errorf("%w: %w", ErrAPIKeyExpired, err)
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidAPIKey, err)
+}
+
 // CreateBatch submits a batch of URLs for processing. Returns batch metadata without polling.
 func (c *Client) CreateBatch(ctx context.Context, opts *BatchOptions) (*BatchResult, error) {
 	body := map[string]interface{}{
@@ -249,22 +1226,19 @@ func (c *Client) CreateBatch(ctx context.Context, opts *BatchOptions) (*BatchRes
 		body["callbackUrl"] = opts.CallbackURL
 	}
 
-	raw, err := c.doJSONRaw(ctx, http.MethodPost, "/jobs/batch", body)
+	respBody, err := c.doRaw(ctx, http.MethodPost, "/jobs/batch", body)
+	if err != nil {
+		return nil, err
+	}
+	data, ok, err := unwrapData(respBody)
 	if err != nil {
 		return nil, err
 	}
-
-	// Unwrap data envelope
-	dataVal, ok := raw["data"]
 	if !ok {
 		return nil, fmt.Errorf("framequery: missing data in batch response")
 	}
-	b, err := json.Marshal(dataVal)
-	if err != nil {
-		return nil, fmt.Errorf("framequery: marshal batch data: %w", err)
-	}
 	var result batchAPIResponse
-	if err := json.Unmarshal(b, &result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("framequery: unmarshal batch data: %w", err)
 	}
 
@@ -276,12 +1250,8 @@ func (c *Client) CreateBatch(ctx context.Context, opts *BatchOptions) (*BatchRes
 }
 
 // ProcessBatch submits a batch and polls until ALL jobs complete or first failure.
+// With opts.ReserveHours set, it holds a reservation for the duration.
 func (c *Client) ProcessBatch(ctx context.Context, opts *BatchOptions) ([]*ProcessingResult, error) {
-	batch, err := c.CreateBatch(ctx, opts)
-	if err != nil {
-		return nil, err
-	}
-
 	interval := defaultPollInterval
 	timeout := defaultTimeout
 	if opts.PollInterval > 0 {
@@ -291,6 +1261,19 @@ func (c *Client) ProcessBatch(ctx context.Context, opts *BatchOptions) ([]*Proce
 		timeout = opts.Timeout
 	}
 
+	if opts.ReserveHours > 0 {
+		r, err := c.ReserveCredits(ctx, opts.ReserveHours, timeout)
+		if err != nil {
+			return nil, err
+		}
+		defer c.ReleaseReservation(context.WithoutCancel(ctx), r)
+	}
+
+	batch, err := c.CreateBatch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -312,12 +1295,11 @@ func (c *Client) ProcessBatch(ctx context.Context, opts *BatchOptions) ([]*Proce
 			if err != nil {
 				return nil, err
 			}
-			if job.IsFailed() {
-				msg, _ := job.Raw["errorMessage"].(string)
-				return nil, &Error{Message: fmt.Sprintf("batch job %s failed: %s", jobID, msg)}
+			if err := jobFailure(job); err != nil {
+				return nil, err
 			}
 			if job.IsComplete() {
-				results[jobID] = parseResult(job.Raw)
+				results[jobID] = job.result()
 			}
 		}
 
@@ -356,21 +1338,190 @@ func (c *Client) GetAudioTrack(ctx context.Context, jobID string, trackIndex int
 	return &track, nil
 }
 
+// WaitForJob polls an already-submitted job until it finishes or fails.
+// Useful when one process submits jobs and another waits on them.
+//
+// Set ProcessOptions.JustCreated if the job was submitted moments ago, so
+// early 404s from replication lag are retried rather than returned.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, opts *ProcessOptions) (*ProcessingResult, error) {
+	return c.waitForJob(ctx, jobID, opts, opts != nil && opts.JustCreated)
+}
+
 // ---- Private ----
 
-func (c *Client) poll(ctx context.Context, jobID string, opts *ProcessOptions) (*ProcessingResult, error) {
+// applyCreateOptions adds the job-creation fields of opts to a POST /jobs or
+// POST /jobs/from-url body. ExtraFields go last and never replace a key
+// already in body.
+func applyCreateOptions(body map[string]interface{}, opts *UploadOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.SourceDuration > 0 && opts.MaxSourceDuration > 0 && opts.SourceDuration > opts.MaxSourceDuration {
+		return &SourceTooLongError{Duration: opts.SourceDuration, Max: opts.MaxSourceDuration}
+	}
+	if opts.SceneThreshold < 0 || opts.SceneThreshold > 1 {
+		return fmt.Errorf("framequery: scene threshold %g outside [0, 1]", opts.SceneThreshold)
+	}
+	if opts.MaxScenes < 0 {
+		return fmt.Errorf("framequery: negative max scenes %d", opts.MaxScenes)
+	}
+	if opts.SummaryMaxWords < 0 {
+		return fmt.Errorf("framequery: negative summary max words %d", opts.SummaryMaxWords)
+	}
+	if opts.CallbackURL != "" {
+		body["callbackUrl"] = opts.CallbackURL
+	}
+	if opts.ProcessingMode != "" {
+		body["processingMode"] = opts.ProcessingMode
+	}
+	if opts.IdempotencyKey != "" {
+		body["idempotencyKey"] = opts.IdempotencyKey
+	}
+	if len(opts.AudioTracks) > 0 {
+		body["audioTracks"] = opts.AudioTracks
+	}
+	if opts.TranscriptDisabled {
+		body["features"] = withoutFeature(opts.Features, FeatureTranscript)
+	} else if len(opts.Features) > 0 {
+		body["features"] = opts.Features
+	}
+	if opts.Language != "" {
+		body["language"] = opts.Language
+	}
+	if opts.AutoDetect {
+		body["autoDetectLanguage"] = true
+	}
+	if opts.EnableDiarization {
+		body["diarization"] = true
+	}
+	if len(opts.Metadata) > 0 {
+		body["metadata"] = opts.Metadata
+	}
+	if len(opts.Tags) > 0 {
+		body["tags"] = opts.Tags
+	}
+	if opts.SceneThreshold > 0 {
+		body["sceneThreshold"] = opts.SceneThreshold
+	}
+	if opts.MaxScenes > 0 {
+		body["maxScenes"] = opts.MaxScenes
+	}
+	if opts.IncludeSummary {
+		body["summary"] = true
+		if opts.SummaryMaxWords > 0 {
+			body["summaryMaxWords"] = opts.SummaryMaxWords
+		}
+	}
+	if opts.IncludeKeywords {
+		body["keywords"] = true
+	}
+	if opts.IncludeTopics {
+		body["topics"] = true
+	}
+	for k, v := range opts.ExtraFields {
+		if _, set := body[k]; !set {
+			body[k] = v
+		}
+	}
+	return nil
+}
+
+// withoutFeature returns fs minus f. A nil fs means every feature.
+func withoutFeature(fs []Feature, f Feature) []Feature {
+	if fs == nil {
+		fs = []Feature{FeatureScenes, FeatureTranscript}
+	}
+	out := []Feature{}
+	for _, x := range fs {
+		if x != f {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// waitForJob is the polling loop behind WaitForJob, plus
+// ReturnPartialOnTimeout. When justCreated is set, 404s during the first
+// NotFoundGracePeriod are treated as transient.
+func (c *Client) waitForJob(ctx context.Context, jobID string, opts *ProcessOptions, justCreated bool) (*ProcessingResult, error) {
+	result, err := c.pollJob(ctx, jobID, opts, justCreated)
+	if err == nil || opts == nil || !opts.ReturnPartialOnTimeout || !errors.Is(err, context.DeadlineExceeded) {
+		return result, err
+	}
+	return c.partialResult(ctx, jobID, opts, err)
+}
+
+// partialResult fetches a timed-out job one last time and returns whatever
+// processed data it has, with ErrPartialResult. Without any, it returns
+// timeoutErr. A job that finished in the meantime is returned as usual.
+func (c *Client) partialResult(ctx context.Context, jobID string, opts *ProcessOptions, timeoutErr error) (*ProcessingResult, error) {
+	reqTimeout := defaultPollReqTimeout
+	if opts.PollRequestTimeout > 0 {
+		reqTimeout = opts.PollRequestTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reqTimeout)
+	defer cancel()
+	job, err := c.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, timeoutErr
+	}
+	if job.IsComplete() {
+		return job.result(), nil
+	}
+	if jobFailure(job) != nil || !job.hasProcessedData() {
+		return nil, timeoutErr
+	}
+	result := job.result()
+	result.Status = StatusPartial
+	return result, fmt.Errorf("framequery: job %s still %s at timeout: %w", jobID, job.Status, ErrPartialResult)
+}
+
+// pollJob polls jobID until it finishes, fails, or times out.
+func (c *Client) pollJob(ctx context.Context, jobID string, opts *ProcessOptions, justCreated bool) (*ProcessingResult, error) {
 	interval := defaultPollInterval
+	maxInterval := defaultMaxPollInterval
 	timeout := defaultTimeout
-	var onProgress func(*Job)
+	pollReqTimeout := defaultPollReqTimeout
+	notFoundGrace := defaultNotFoundGrace
+	maxPolls := 0
+	initialDelayFromETA := false
+	var maxInitialDelay time.Duration
+	captureRaw := c.captureRaw
+	var rawWriter io.Writer
+	var onStart, onProgress func(*Job)
+	var onProgressErr func(*Job) error
+	var events *eventWriter
 
 	if opts != nil {
 		if opts.PollInterval > 0 {
 			interval = opts.PollInterval
 		}
+		if opts.MaxPollInterval > 0 {
+			maxInterval = opts.MaxPollInterval
+		}
 		if opts.Timeout > 0 {
 			timeout = opts.Timeout
 		}
+		if opts.PollRequestTimeout > 0 {
+			pollReqTimeout = opts.PollRequestTimeout
+		}
+		if opts.NotFoundGracePeriod > 0 {
+			notFoundGrace = opts.NotFoundGracePeriod
+		}
+		maxPolls = opts.MaxPolls
+		initialDelayFromETA = opts.InitialDelayFromETA
+		if opts.MaxInitialDelay > 0 {
+			maxInitialDelay = opts.MaxInitialDelay
+		}
+		captureRaw = captureRaw || opts.CaptureRawResponse
+		rawWriter = opts.RawResponseWriter
+		onStart = opts.OnStart
 		onProgress = opts.OnProgress
+		onProgressErr = opts.OnProgressErr
+		events = newEventWriter(opts.EventWriter)
+	}
+	if maxInitialDelay == 0 {
+		maxInitialDelay = maxInterval
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -379,36 +1530,127 @@ func (c *Client) poll(ctx context.Context, jobID string, opts *ProcessOptions) (
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	for _, hook := range c.pollHooks {
+		if done := hook(jobID); done != nil {
+			defer done()
+		}
+	}
+
+	start := time.Now()
+	first := true
+	var lastStatus JobStatus
+	polls := 0
 	for {
-		job, err := c.GetJob(ctx, jobID)
+		pollCtx, pollCancel := context.WithTimeout(ctx, pollReqTimeout)
+		job, err := c.getJob(pollCtx, jobID, captureRaw)
+		stuck := pollCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+		pollCancel()
 		if err != nil {
+			if stuck {
+				// A single hung request; it counts as a poll, and the outer
+				// timeout still bounds the wait
+				if polls++; maxPolls > 0 && polls >= maxPolls {
+					return nil, fmt.Errorf("framequery: job %s unanswered after %d polls: %w", jobID, polls, ErrMaxPollsExceeded)
+				}
+				ticker.Reset(interval)
+				select {
+				case <-ctx.Done():
+					return nil, fmt.Errorf("framequery: timed out waiting for job %s: %w", jobID, ctx.Err())
+				case <-ticker.C:
+				}
+				continue
+			}
+			if justCreated && IsNotFoundError(err) && time.Since(start) < notFoundGrace {
+				// The job may not be visible on read replicas yet
+				select {
+				case <-ctx.Done():
+					return nil, fmt.Errorf("framequery: timed out waiting for job %s: %w", jobID, ctx.Err())
+				case <-time.After(notFoundRetryDelay):
+				}
+				continue
+			}
 			return nil, err
 		}
 
+		events.emit(ProgressEvent{Type: EventPoll, JobID: jobID, Status: job.Status, ETASeconds: job.ETASeconds})
+		if job.Status != lastStatus {
+			events.emit(ProgressEvent{Type: EventStatusChange, JobID: jobID, Status: job.Status, PrevStatus: lastStatus})
+			lastStatus = job.Status
+		}
+
+		if onStart != nil && !isPending(job.Status) {
+			fn := onStart
+			onStart = nil
+			if err := callSafely("OnStart", func() { fn(job) }); err != nil {
+				return nil, fmt.Errorf("framequery: polling job %s aborted: %w", jobID, err)
+			}
+		}
 		if onProgress != nil {
-			onProgress(job)
+			if err := callSafely("OnProgress", func() { onProgress(job) }); err != nil {
+				return nil, fmt.Errorf("framequery: polling job %s aborted: %w", jobID, err)
+			}
+		}
+		if onProgressErr != nil {
+			var cbErr error
+			if err := callSafely("OnProgressErr", func() { cbErr = onProgressErr(job) }); err != nil {
+				return nil, fmt.Errorf("framequery: polling job %s aborted: %w", jobID, err)
+			}
+			if cbErr != nil {
+				return nil, fmt.Errorf("framequery: polling job %s aborted: %w", jobID, cbErr)
+			}
 		}
 
-		if job.IsFailed() {
-			msg, _ := job.Raw["errorMessage"].(string)
-			return nil, &Error{Message: fmt.Sprintf("job %s failed: %s", jobID, msg)}
+		if c.dedup != nil && job.IsTerminal() {
+			c.dedup.forgetJob(jobID)
+		}
+		if err := jobFailure(job); err != nil {
+			events.emit(ProgressEvent{Type: EventTerminal, JobID: jobID, Status: job.Status, Error: err.Error()})
+			return nil, err
 		}
 
 		if job.IsComplete() {
-			return parseResult(job.Raw), nil
+			if rawWriter != nil {
+				// Fetch the finished job once more, streaming it to rawWriter
+				if job, err = c.streamJob(ctx, jobID, rawWriter, captureRaw); err != nil {
+					return nil, err
+				}
+			}
+			events.emit(ProgressEvent{Type: EventTerminal, JobID: jobID, Status: job.Status})
+			result := job.result()
+			result.RawBodySHA256 = job.RawBodySHA256
+			if captureRaw {
+				result.RawBody = job.RawBody
+			}
+			return result, nil
+		}
+		if job.hasProcessedData() {
+			events.emit(ProgressEvent{Type: EventPartialResult, JobID: jobID, Status: job.Status})
+		}
+		if polls++; maxPolls > 0 && polls >= maxPolls {
+			return nil, fmt.Errorf("framequery: job %s still %s after %d polls: %w", jobID, job.Status, polls, ErrMaxPollsExceeded)
 		}
 
 		// Adaptive interval
 		currentInterval := interval
-		if job.ETASeconds > 60 {
-			adaptive := time.Duration(job.ETASeconds/3) * time.Second
-			if adaptive > 30*time.Second {
-				adaptive = 30 * time.Second
+		if eta := job.ETADuration(); eta > time.Minute {
+			adaptive := eta / 3
+			if adaptive > maxInterval {
+				adaptive = maxInterval
 			}
 			currentInterval = adaptive
-			ticker.Reset(currentInterval)
 		}
-		_ = currentInterval
+		// Skip most of the expected wait before the second poll
+		if first && initialDelayFromETA && job.ETASeconds > 0 {
+			delay := job.ETADuration() / 2
+			if delay > maxInitialDelay {
+				delay = maxInitialDelay
+			}
+			if delay > currentInterval {
+				currentInterval = delay
+			}
+		}
+		first = false
+		ticker.Reset(currentInterval)
 
 		select {
 		case <-ctx.Done():
@@ -418,30 +1660,254 @@ func (c *Client) poll(ctx context.Context, jobID string, opts *ProcessOptions) (
 	}
 }
 
+// callSafely runs a user callback, turning a panic into a *CallbackPanicError.
+func callSafely(name string, fn func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &CallbackPanicError{Callback: name, Value: v, Stack: debug.Stack()}
+		}
+	}()
+	fn()
+	return nil
+}
+
+type jobState int
+
+const (
+	stateRunning jobState = iota
+	stateComplete
+	stateFailed
+	stateCancelled
+	stateExpired
+)
+
+// jobStates classifies every status the API documents. Statuses not listed
+// here are FAILED variants if they contain "FAILED", else running.
+var jobStates = map[JobStatus]jobState{
+	StatusPendingUpload:         stateRunning,
+	StatusPendingFetch:          stateRunning,
+	StatusQueued:                stateRunning,
+	StatusIngestProcessing:      stateRunning,
+	StatusIngestTranscoding:     stateRunning,
+	StatusIngestCompleted:       stateRunning,
+	StatusVideoProcessing:       stateRunning,
+	StatusVideoCompleted:        stateRunning,
+	StatusVisionProcessing:      stateRunning,
+	StatusVisionCompleted:       stateComplete,
+	StatusCompletedNoScenes:     stateComplete,
+	StatusFailed:                stateFailed,
+	StatusFailedFetch:           stateFailed,
+	StatusIngestFailedTranscode: stateFailed,
+	StatusCancelled:             stateCancelled,
+	StatusExpired:               stateExpired,
+}
+
+func stateOf(status JobStatus) jobState {
+	if s, ok := jobStates[status]; ok {
+		return s
+	}
+	if strings.Contains(string(status), "FAILED") {
+		return stateFailed
+	}
+	return stateRunning
+}
+
+// jobFailure returns the error for a cancelled, failed, or expired job, or nil.
+func jobFailure(job *Job) error {
+	switch stateOf(job.Status) {
+	case stateFailed:
+		return &JobFailedError{JobID: job.ID, Status: job.Status, Message: job.ErrorMessage, Code: job.ErrorCode}
+	case stateCancelled:
+		return fmt.Errorf("framequery: job %s: %w", job.ID, ErrJobCancelled)
+	case stateExpired:
+		return &ResultExpiredError{JobID: job.ID}
+	}
+	return nil
+}
+
+// resultError returns why GetResult can't return job's result, or nil.
+func resultError(job *Job) error {
+	if err := jobFailure(job); err != nil {
+		return err
+	}
+	if stateOf(job.Status) == stateRunning {
+		return &JobNotCompleteError{JobID: job.ID, Status: job.Status, ETASeconds: job.ETASeconds}
+	}
+	if !job.hasProcessedData() {
+		return &ResultNotReadyError{JobID: job.ID, Status: job.Status}
+	}
+	return nil
+}
+
+// getJob fetches a job, optionally keeping the verbatim response body.
+func (c *Client) getJob(ctx context.Context, jobID string, capture bool) (*Job, error) {
+	respBody, err := c.doRaw(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := unwrapData(respBody)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkStrict(data); err != nil {
+		return nil, err
+	}
+	job, err := c.decodeJobBody(data)
+	if err != nil {
+		return nil, err
+	}
+	if capture {
+		job.RawBody = respBody
+		job.RawBodySHA256 = sha256Hex(respBody)
+	}
+	return job, nil
+}
+
+// streamJob fetches a job, copying the verbatim response body to w while it
+// is decoded rather than holding it. capture also keeps it on Job.RawBody.
+func (c *Client) streamJob(ctx context.Context, jobID string, w io.Writer, capture bool) (*Job, error) {
+	path := "/jobs/" + url.PathEscape(jobID)
+	resp, err := c.doResponse(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	sink := &rawSink{w: w}
+	h := sha256.New()
+	dst := io.MultiWriter(sink, h)
+	var kept bytes.Buffer
+	if capture {
+		dst = io.MultiWriter(dst, &kept)
+	}
+	tee := io.TeeReader(resp.Body, dst)
+	var env map[string]json.RawMessage
+	err = json.NewDecoder(tee).Decode(&env)
+	if err == nil {
+		// Trailing bytes belong to the verbatim body too
+		_, err = io.Copy(io.Discard, tee)
+	}
+	if sink.err != nil {
+		return nil, fmt.Errorf("framequery: write raw response: %w", sink.err)
+	}
+	if err != nil {
+		var se *json.SyntaxError
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &se) || errors.As(err, &te) || err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, &Error{Message: "unmarshal response: " + err.Error(), Err: err}
+		}
+		return nil, readResponseError(resp, path, err)
+	}
+
+	data, ok := env["data"]
+	if !ok {
+		data, _ = json.Marshal(env)
+	}
+	if err := c.checkStrict(data); err != nil {
+		return nil, err
+	}
+	job, err := c.decodeJobBody(data)
+	if err != nil {
+		return nil, err
+	}
+	job.RawBodySHA256 = hex.EncodeToString(h.Sum(nil))
+	if capture {
+		job.RawBody = kept.Bytes()
+	}
+	return job, nil
+}
+
+// rawSink passes writes to w, remembering the first error so it can be told
+// apart from a read error on the response.
+type rawSink struct {
+	w   io.Writer
+	err error
+}
+
+func (s *rawSink) Write(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.w.Write(p)
+	s.err = err
+	return n, err
+}
+
 // doJSON makes an API request, unwraps the {"data": ...} envelope, and decodes into out.
 func (c *Client) doJSON(ctx context.Context, method, path string, body any, out any) error {
-	raw, err := c.doJSONRaw(ctx, method, path, body)
+	respBody, err := c.doRaw(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	data, _, err := unwrapData(respBody)
 	if err != nil {
 		return err
 	}
+	return json.Unmarshal(data, out)
+}
+
+// apiPath returns path without its query, as recorded on an Error.
+func apiPath(path string) string {
+	p, _, _ := strings.Cut(path, "?")
+	return p
+}
 
-	// Unwrap "data" envelope
-	dataVal, hasData := raw["data"]
-	if !hasData {
-		// No envelope, decode entire response
-		b, _ := json.Marshal(raw)
-		return json.Unmarshal(b, out)
+// unwrapData returns the "data" member of a response object, or the whole
+// body if it has none.
+func unwrapData(body []byte) (data []byte, ok bool, err error) {
+	var env map[string]json.RawMessage
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, false, &Error{Message: "unmarshal response: " + err.Error(), Err: err}
 	}
+	if d, ok := env["data"]; ok {
+		return d, true, nil
+	}
+	return body, false, nil
+}
 
-	b, err := json.Marshal(dataVal)
+// decodeJobBody decodes a job from response data. An empty or null body is
+// an empty job.
+func (c *Client) decodeJobBody(b []byte) (*Job, error) {
+	if len(b) == 0 || string(b) == "null" {
+		b = []byte("{}")
+	}
+	job, err := decodeJobRaw(b, !c.dropRaw)
 	if err != nil {
-		return fmt.Errorf("framequery: marshal data: %w", err)
+		return nil, fmt.Errorf("framequery: decode job: %w", err)
 	}
-	return json.Unmarshal(b, out)
+	return job, nil
 }
 
 // doJSONRaw makes an API request and returns the raw JSON response. Retries on 5xx/429.
 func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (map[string]any, error) {
+	respBody, err := c.doRaw(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("framequery: unmarshal response: %w", err)
+	}
+	return result, nil
+}
+
+// doRaw makes an API request and returns the verbatim response body. Retries on 5xx/429.
+func (c *Client) doRaw(ctx context.Context, method, path string, body any) ([]byte, error) {
+	resp, err := c.doResponse(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, readResponseError(resp, path, err)
+	}
+	return respBody, nil
+}
+
+// doResponse makes an API request and returns the successful response with
+// its body unread; the caller closes it. Retries on 5xx/429.
+func (c *Client) doResponse(ctx context.Context, method, path string, body any) (*http.Response, error) {
 	apiURL := c.baseURL + path
 
 	var bodyReader io.Reader
@@ -460,7 +1926,8 @@ func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (
 			return nil, fmt.Errorf("framequery: create request: %w", err)
 		}
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
-		req.Header.Set("User-Agent", "framequery-go/"+version)
+		req.Header.Set("User-Agent", c.userAgentHeader())
+		c.setRequestID(req)
 		if body != nil {
 			req.Header.Set("Content-Type", "application/json")
 		}
@@ -469,7 +1936,7 @@ func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (
 		if err != nil {
 			lastErr = err
 			if attempt < c.maxRetries {
-				time.Sleep(backoff(attempt))
+				time.Sleep(c.backoff(attempt))
 				// Reset body reader for retry
 				if body != nil {
 					b, _ := json.Marshal(body)
@@ -477,22 +1944,26 @@ func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (
 				}
 				continue
 			}
-			return nil, fmt.Errorf("framequery: request failed: %w", err)
+			return nil, &Error{Message: "request failed: " + err.Error(), Path: apiPath(path), Err: err}
+		}
+		c.recordRateLimit(resp.Header)
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
 		}
-		defer resp.Body.Close()
 
+		// Close before any retry; a deferred close would hold every attempt's
+		// connection until the last one returns
 		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
 		if err != nil {
-			return nil, fmt.Errorf("framequery: read response: %w", err)
+			return nil, readResponseError(resp, path, err)
 		}
 
 		if resp.StatusCode >= 500 || resp.StatusCode == 429 {
 			if attempt < c.maxRetries {
-				delay := backoff(attempt)
-				if ra := resp.Header.Get("Retry-After"); ra != "" {
-					if secs, err := strconv.ParseFloat(ra, 64); err == nil {
-						delay = time.Duration(secs * float64(time.Second))
-					}
+				delay := c.backoff(attempt)
+				if ra, ok := parseRetryAfter(resp.Header); ok {
+					delay = ra
 				}
 				time.Sleep(delay)
 				if body != nil {
@@ -503,28 +1974,25 @@ func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (
 			}
 		}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			apiErr := &Error{StatusCode: resp.StatusCode}
-			var errBody map[string]any
-			if json.Unmarshal(respBody, &errBody) == nil {
-				if msg, ok := errBody["error"].(string); ok {
-					apiErr.Message = msg
-				} else if msg, ok := errBody["message"].(string); ok {
-					apiErr.Message = msg
-				}
-				apiErr.Body = errBody
-			}
-			if apiErr.Message == "" {
-				apiErr.Message = string(respBody)
+		apiErr := &Error{
+			StatusCode: resp.StatusCode,
+			RequestID:  resp.Header.Get("X-Request-Id"),
+			Path:       apiPath(path),
+		}
+		apiErr.RetryAfter, _ = parseRetryAfter(resp.Header)
+		var errBody map[string]any
+		if json.Unmarshal(respBody, &errBody) == nil {
+			if msg, ok := errBody["error"].(string); ok {
+				apiErr.Message = msg
+			} else if msg, ok := errBody["message"].(string); ok {
+				apiErr.Message = msg
 			}
-			return nil, apiErr
+			apiErr.Body = errBody
 		}
-
-		var result map[string]any
-		if err := json.Unmarshal(respBody, &result); err != nil {
-			return nil, fmt.Errorf("framequery: unmarshal response: %w", err)
+		if apiErr.Message == "" {
+			apiErr.Message = string(respBody)
 		}
-		return result, nil
+		return nil, apiErr
 	}
 
 	if lastErr != nil {
@@ -533,10 +2001,44 @@ func (c *Client) doJSONRaw(ctx context.Context, method, path string, body any) (
 	return nil, fmt.Errorf("framequery: request failed")
 }
 
-func backoff(attempt int) time.Duration {
-	ms := 500.0 * math.Pow(2, float64(attempt))
-	if ms > 30000 {
-		ms = 30000
+// readResponseError reports a failure reading resp's body.
+func readResponseError(resp *http.Response, path string, err error) error {
+	return &Error{
+		Message:   "read response: " + err.Error(),
+		RequestID: resp.Header.Get("X-Request-Id"),
+		Path:      apiPath(path),
+		Err:       err,
+	}
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// backoff returns the delay before retry attempt+1: full jitter, uniform in
+// [0, min(30s, 500ms * 2^attempt)), so clients retrying together spread out.
+func (c *Client) backoff(attempt int) time.Duration {
+	ceil := 500.0 * math.Pow(2, float64(attempt))
+	if ceil > 30000 {
+		ceil = 30000
 	}
-	return time.Duration(ms) * time.Millisecond
+	return time.Duration(c.randSource.float64() * ceil * float64(time.Millisecond))
+}
+
+// lockedSource makes a rand.Source safe for concurrent use. Clones share it.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func newLockedSource(src rand.Source) *lockedSource {
+	return &lockedSource{src: src}
+}
+
+// float64 returns a number in [0, 1).
+func (s *lockedSource) float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return float64(s.src.Int63()) / (1 << 63)
 }
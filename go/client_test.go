@@ -0,0 +1,1277 @@
+package framequery
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSourceTooLong(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"PENDING_FETCH"}}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	_, err := c.ProcessURL(ctx, "https://cdn.example.com/long.mp4", &ProcessOptions{SourceDuration: 3 * time.Hour, MaxSourceDuration: 2 * time.Hour})
+	var tl *SourceTooLongError
+	if !errors.As(err, &tl) || !errors.Is(err, ErrSourceTooLong) || tl.Duration != 3*time.Hour || tl.Max != 2*time.Hour {
+		t.Fatalf("err = %v, want a *SourceTooLongError for 3h over 2h", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests sent, want none", requests)
+	}
+
+	for _, opts := range []*UploadOptions{
+		{SourceDuration: time.Hour, MaxSourceDuration: 2 * time.Hour},
+		{SourceDuration: 3 * time.Hour}, // no limit given
+	} {
+		if _, err := c.SubmitURL(ctx, "https://cdn.example.com/ok.mp4", opts); err != nil {
+			t.Errorf("SubmitURL(%+v) = %v", opts, err)
+		}
+	}
+}
+
+func TestListJobsSort(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	if _, err := c.ListJobs(ctx, &ListJobsOptions{SortBy: SortByDuration, SortOrder: SortOrderAsc}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("sortBy") != "duration" || got.Get("sortOrder") != "asc" {
+		t.Errorf("query = %v, want sortBy=duration&sortOrder=asc", got)
+	}
+
+	if _, err := c.ListJobs(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got.Has("sortBy") || got.Has("sortOrder") {
+		t.Errorf("query = %v, want no sort parameters by default", got)
+	}
+
+	multi := &ListJobsOptions{Statuses: []JobStatus{StatusFailed, StatusCancelled}}
+	multi.SortBy, multi.SortOrder = SortByCreatedAt, SortOrderDesc
+	if _, err := c.ListJobs(ctx, multi); err != nil {
+		t.Errorf("merged listing newest first: %v", err)
+	}
+	multi.SortBy = SortByStatus
+	if _, err := c.ListJobs(ctx, multi); err == nil {
+		t.Error("merged listing sorted by status: want an error")
+	}
+}
+
+func TestApplyCreateOptionsSummary(t *testing.T) {
+	body := map[string]any{}
+	if err := applyCreateOptions(body, &UploadOptions{IncludeSummary: true, SummaryMaxWords: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if body["summary"] != true || body["summaryMaxWords"] != 50 {
+		t.Errorf("body = %v", body)
+	}
+
+	body = map[string]any{}
+	if err := applyCreateOptions(body, &UploadOptions{SummaryMaxWords: 50}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["summaryMaxWords"]; ok {
+		t.Errorf("summaryMaxWords sent without IncludeSummary: %v", body)
+	}
+	if err := applyCreateOptions(map[string]any{}, &UploadOptions{SummaryMaxWords: -1}); err == nil {
+		t.Error("negative SummaryMaxWords: want an error")
+	}
+}
+
+// fixedSource returns the same Int63 every time.
+type fixedSource int64
+
+func (s fixedSource) Int63() int64 { return int64(s) }
+func (fixedSource) Seed(int64)     {}
+
+func TestBackoffFullJitter(t *testing.T) {
+	c := New("k")
+	c.randSource = newLockedSource(fixedSource(1 << 62)) // 0.5
+	for attempt, want := range []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second} {
+		if got := c.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	if got := c.backoff(20); got != 15*time.Second {
+		t.Errorf("backoff(20) = %v, want half the 30s cap", got)
+	}
+
+	c.randSource = newLockedSource(fixedSource(0))
+	if got := c.backoff(3); got != 0 {
+		t.Errorf("backoff with a zero draw = %v, want 0", got)
+	}
+
+	c = New("k")
+	for attempt := 0; attempt < 10; attempt++ {
+		ceil := 500 * time.Millisecond << attempt
+		if ceil > 30*time.Second {
+			ceil = 30 * time.Second
+		}
+		if got := c.backoff(attempt); got < 0 || got >= ceil {
+			t.Errorf("backoff(%d) = %v, outside [0, %v)", attempt, got, ceil)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	sdk := "framequery-go/" + version
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, sdk},
+		{"replaced", []Option{WithUserAgent("custom/2.0")}, "custom/2.0"},
+		{"prefixed", []Option{WithUserAgentPrefix("my-app/1.0")}, "my-app/1.0 " + sdk},
+		{"both", []Option{WithUserAgent("custom/2.0"), WithUserAgentPrefix("my-app/1.0")}, "my-app/1.0 custom/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("User-Agent")
+				w.Write([]byte(`{"data":{}}`))
+			}))
+			defer srv.Close()
+			if err := New("k", append(tt.opts, WithBaseURL(srv.URL))...).Ping(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/quota" {
+			t.Errorf("unexpected %s %s; Ping should read the quota", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer good" {
+			http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"data":{"plan":"pro"}}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := New("good", WithBaseURL(srv.URL)).Ping(ctx); err != nil {
+		t.Errorf("good key: %v", err)
+	}
+	if err := New("bad", WithBaseURL(srv.URL)).Ping(ctx); !IsAuthError(err) {
+		t.Errorf("bad key: err = %v, want an auth error", err)
+	}
+	srv.Close()
+	if err := New("good", WithBaseURL(srv.URL), WithMaxRetries(0)).Ping(ctx); err == nil || IsAuthError(err) {
+		t.Errorf("server down: err = %v, want a non-auth error", err)
+	}
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		body           string
+		invalid, expir bool
+	}{
+		{"valid", 200, `{"data":{"plan":"pro"}}`, false, false},
+		{"invalid", 401, `{"error":"invalid api key"}`, true, false},
+		{"expired", 401, `{"error":"unauthorized","code":"api_key_expired"}`, true, true},
+		{"forbidden", 403, `{"error":"forbidden"}`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			err := New("fq_k", WithBaseURL(srv.URL)).ValidateAPIKey(context.Background())
+			if (tt.status == 200) != (err == nil) {
+				t.Fatalf("err = %v", err)
+			}
+			if errors.Is(err, ErrInvalidAPIKey) != tt.invalid || errors.Is(err, ErrAPIKeyExpired) != tt.expir {
+				t.Errorf("err = %v: invalid %v, expired %v; want %v, %v", err, errors.Is(err, ErrInvalidAPIKey), errors.Is(err, ErrAPIKeyExpired), tt.invalid, tt.expir)
+			}
+			if tt.invalid && !IsAuthError(err) {
+				t.Errorf("err = %v lost the underlying *Error", err)
+			}
+		})
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	srv.Close()
+	err := New("fq_k", WithBaseURL(srv.URL), WithMaxRetries(0)).ValidateAPIKey(context.Background())
+	if err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("network failure: err = %v, want a non-key error", err)
+	}
+
+	c := New("")
+	c.apiKey = ""
+	if err := c.ValidateAPIKey(context.Background()); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("empty key: err = %v", err)
+	}
+}
+
+func TestWithoutRaw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		job := `{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":5,"scenes":[{"description":"a","endTs":5}]}}`
+		if r.URL.Path == "/jobs" {
+			w.Write([]byte(`{"data":[` + job + `,` + job + `]}`))
+			return
+		}
+		w.Write([]byte(`{"data":` + job + `}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	for _, drop := range []bool{false, true} {
+		var opts []Option
+		if drop {
+			opts = append(opts, WithoutRaw())
+		}
+		c := New("k", append(opts, WithBaseURL(srv.URL))...)
+		page, err := c.ListJobs(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		job, err := c.GetJob(ctx, "j1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, j := range append(page.Jobs, *job) {
+			if (j.Raw == nil) != drop {
+				t.Errorf("drop %v: Raw = %v", drop, j.Raw)
+			}
+			r, ok := j.Result()
+			if !ok || r.Duration != 5 || len(r.Scenes) != 1 || (r.Raw == nil) != drop {
+				t.Errorf("drop %v: Result() = %+v, %v", drop, r, ok)
+			}
+		}
+		if r, err := c.GetResult(ctx, "j1"); err != nil || r.Scenes[0].Description != "a" {
+			t.Errorf("drop %v: GetResult = %+v, %v", drop, r, err)
+		}
+	}
+}
+
+func TestApplyCreateOptionsEnrichment(t *testing.T) {
+	body := map[string]any{}
+	opts := (&ProcessOptions{IncludeKeywords: true, IncludeTopics: true}).uploadOptions()
+	if err := applyCreateOptions(body, opts); err != nil {
+		t.Fatal(err)
+	}
+	if body["keywords"] != true || body["topics"] != true {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestWithRequestIDHeader(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Trace-Id"))
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+	ctx := WithRequestID(context.Background(), "trace-1")
+
+	c := New("k", WithBaseURL(srv.URL), WithRequestIDHeader("x-trace-id"))
+	c.GetQuota(ctx)
+	c.GetQuota(context.Background())
+	New("k", WithBaseURL(srv.URL)).GetQuota(ctx)
+	if want := []string{"trace-1", "", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("headers = %q, want %q", got, want)
+	}
+	if id, ok := RequestIDFromContext(ctx); !ok || id != "trace-1" {
+		t.Errorf("RequestIDFromContext = %q, %v", id, ok)
+	}
+}
+
+func TestReturnPartialOnTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		job     string
+		partial bool
+		wantErr error
+	}{
+		{"partial data", `{"jobId":"j","status":"VISION_PROCESSING","processedData":{"length":30,"scenes":[{"description":"intro","endTs":5}]}}`, true, ErrPartialResult},
+		{"no data", `{"jobId":"j","status":"VIDEO_PROCESSING"}`, true, context.DeadlineExceeded},
+		{"option off", `{"jobId":"j","status":"VISION_PROCESSING","processedData":{"scenes":[]}}`, false, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"data":` + tt.job + `}`))
+			}))
+			defer srv.Close()
+			c := New("k", WithBaseURL(srv.URL))
+			r, err := c.WaitForJob(context.Background(), "j", &ProcessOptions{
+				PollInterval:           5 * time.Millisecond,
+				Timeout:                30 * time.Millisecond,
+				ReturnPartialOnTimeout: tt.partial,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != ErrPartialResult {
+				if r != nil {
+					t.Errorf("result = %+v, want nil", r)
+				}
+				return
+			}
+			if r == nil || r.Status != StatusPartial || len(r.Scenes) != 1 || r.Duration != 30 {
+				t.Errorf("result = %+v", r)
+			}
+		})
+	}
+}
+
+func TestCloneKeepsMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	calls := map[string]int{}
+	counter := func(name string) Interceptor {
+		return InterceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+			calls[name]++
+			return next.RoundTrip(req)
+		})
+	}
+	var debug bytes.Buffer
+	parent := New("k", WithBaseURL(srv.URL), WithMiddleware(counter("parent")), WithDebug(&debug))
+	clones := map[string]*Client{
+		"http client":    parent.Clone(WithHTTPClient(&http.Client{})),
+		"profile":        parent.Clone(WithTransportProfile(ProfileBulk)),
+		"middleware":     parent.Clone(WithMiddleware(counter("clone"))),
+		"clone of clone": parent.Clone(WithAPIKey("k2")).Clone(WithHTTPClient(&http.Client{Timeout: time.Minute})),
+	}
+	for name, c := range clones {
+		calls = map[string]int{}
+		debug.Reset()
+		if _, err := c.GetQuota(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]int{"parent": 1}
+		if name == "middleware" {
+			want["clone"] = 1
+		}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("%s: interceptor calls = %v, want %v", name, calls, want)
+		}
+		if n := strings.Count(debug.String(), "> GET "); n != 1 {
+			t.Errorf("%s: %d debug entries, want 1:\n%s", name, n, debug.String())
+		}
+	}
+
+	calls = map[string]int{}
+	if _, err := parent.GetQuota(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(calls, map[string]int{"parent": 1}) {
+		t.Errorf("parent: interceptor calls = %v; a clone changed it", calls)
+	}
+}
+
+func TestCreateJobHook(t *testing.T) {
+	var sent map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"PENDING_FETCH"}}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	opts := &UploadOptions{ExtraFields: map[string]any{"url": "https://evil.example.com/x.mp4", "priority": 3.0}}
+
+	var seen []string
+	c := New("k", WithBaseURL(srv.URL),
+		WithCreateJobHook(func(_ context.Context, path string, body map[string]any) error {
+			seen = append(seen, path)
+			body["auditId"] = "a1"
+			return nil
+		}),
+		WithCreateJobHook(func(_ context.Context, _ string, body map[string]any) error {
+			if body["auditId"] != "a1" {
+				t.Errorf("second hook saw %v", body)
+			}
+			return nil
+		}))
+	if _, err := c.SubmitURL(ctx, "https://cdn.example.com/v.mp4", opts); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"url": "https://cdn.example.com/v.mp4", "priority": 3.0, "auditId": "a1"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+	if !reflect.DeepEqual(seen, []string{"/jobs/from-url"}) {
+		t.Errorf("hook paths = %v", seen)
+	}
+
+	sent = nil
+	denied := errors.New("denied")
+	c = New("k", WithBaseURL(srv.URL), WithCreateJobHook(func(context.Context, string, map[string]any) error { return denied }))
+	if _, err := c.SubmitURL(ctx, "https://cdn.example.com/v.mp4", opts); !errors.Is(err, denied) {
+		t.Errorf("err = %v, want %v", err, denied)
+	}
+	if sent != nil {
+		t.Error("request sent after the hook failed")
+	}
+}
+
+// failWriter fails every write.
+type failWriter struct{ err error }
+
+func (w failWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestRawResponseWriter(t *testing.T) {
+	const done = `{ "data": {"status":"VISION_COMPLETED", "jobId":"j1", "processedData":{"length":2.50}} }` + "\n"
+	var mu sync.Mutex
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if polls++; polls == 1 {
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"VIDEO_PROCESSING"}}`))
+			return
+		}
+		w.Write([]byte(done))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	sum := sha256.Sum256([]byte(done))
+	wantSum := hex.EncodeToString(sum[:])
+
+	for _, capture := range []bool{false, true} {
+		polls = 0
+		var buf bytes.Buffer
+		r, err := c.WaitForJob(context.Background(), "j1", &ProcessOptions{
+			PollInterval:       time.Millisecond,
+			RawResponseWriter:  &buf,
+			CaptureRawResponse: capture,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != done {
+			t.Errorf("capture %v: writer got %q, want only the final body %q", capture, buf.String(), done)
+		}
+		if r.RawBodySHA256 != wantSum || r.Duration != 2.5 {
+			t.Errorf("capture %v: result = %+v", capture, r)
+		}
+		if capture != (string(r.RawBody) == done) {
+			t.Errorf("capture %v: RawBody = %q", capture, r.RawBody)
+		}
+	}
+
+	polls = 0
+	full := errors.New("disk full")
+	_, err := c.WaitForJob(context.Background(), "j1", &ProcessOptions{PollInterval: time.Millisecond, RawResponseWriter: failWriter{full}})
+	if !errors.Is(err, full) || !strings.Contains(err.Error(), "write raw response") {
+		t.Errorf("err = %v, want the writer's error", err)
+	}
+}
+
+func TestInitialDelayFromETA(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ProcessOptions
+		wantPolls int
+		wantGap   time.Duration // minimum wait before the second poll
+		wantErr   error
+	}{
+		{"off", ProcessOptions{}, 4, 0, nil},
+		{"on, capped by MaxPollInterval", ProcessOptions{InitialDelayFromETA: true}, 4, 0, nil},
+		{"on, longer MaxPollInterval", ProcessOptions{InitialDelayFromETA: true, MaxPollInterval: 100 * time.Millisecond}, 4, 100 * time.Millisecond, nil},
+		{"on, MaxInitialDelay", ProcessOptions{InitialDelayFromETA: true, MaxInitialDelay: 100 * time.Millisecond}, 4, 100 * time.Millisecond, nil},
+		{"on, uncapped", ProcessOptions{InitialDelayFromETA: true, MaxInitialDelay: time.Hour}, 1, 0, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var polls []time.Time
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				polls = append(polls, time.Now())
+				n := len(polls)
+				mu.Unlock()
+				if n < 4 {
+					w.Write([]byte(`{"data":{"jobId":"j","status":"VIDEO_PROCESSING","estimatedCompletionTimeSeconds":2}}`))
+					return
+				}
+				w.Write([]byte(`{"data":{"jobId":"j","status":"VISION_COMPLETED","processedData":{"length":2}}}`))
+			}))
+			defer srv.Close()
+			opts := tt.opts
+			opts.PollInterval = 5 * time.Millisecond
+			if opts.MaxPollInterval == 0 {
+				opts.MaxPollInterval = 5 * time.Millisecond
+			}
+			opts.Timeout = 500 * time.Millisecond
+
+			_, err := New("k", WithBaseURL(srv.URL)).WaitForJob(context.Background(), "j", &opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(polls) != tt.wantPolls {
+				t.Errorf("%d polls, want %d", len(polls), tt.wantPolls)
+			}
+			if tt.wantGap > 0 && len(polls) > 1 {
+				if gap := polls[1].Sub(polls[0]); gap < tt.wantGap {
+					t.Errorf("second poll after %v, want at least %v", gap, tt.wantGap)
+				}
+			}
+		})
+	}
+}
+
+func TestCallbackPanic(t *testing.T) {
+	boom := func(*Job) { panic("boom") }
+	tests := []struct {
+		callback string
+		opts     ProcessOptions
+	}{
+		{"OnStart", ProcessOptions{OnStart: boom}},
+		{"OnProgress", ProcessOptions{OnProgress: boom}},
+		{"OnProgressErr", ProcessOptions{OnProgressErr: func(j *Job) error { boom(j); return nil }}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.callback, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("unexpected %s %s; the job should keep running", r.Method, r.URL.Path)
+				}
+				w.Write([]byte(`{"data":{"jobId":"j1","status":"VIDEO_PROCESSING"}}`))
+			}))
+			defer srv.Close()
+			opts := tt.opts
+			opts.PollInterval = time.Millisecond
+
+			_, err := New("k", WithBaseURL(srv.URL)).WaitForJob(context.Background(), "j1", &opts)
+			var pe *CallbackPanicError
+			if !errors.As(err, &pe) {
+				t.Fatalf("err = %v, want a *CallbackPanicError", err)
+			}
+			if pe.Callback != tt.callback || pe.Value != "boom" || !bytes.Contains(pe.Stack, []byte("TestCallbackPanic")) {
+				t.Errorf("CallbackPanicError = %s, %v, stack %d bytes", pe.Callback, pe.Value, len(pe.Stack))
+			}
+			if !strings.Contains(err.Error(), tt.callback+" callback panicked: boom") {
+				t.Errorf("message = %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestOnProgressErrAbortsPolling(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s; the job should keep running", r.Method, r.URL.Path)
+		}
+		polls++
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"VIDEO_PROCESSING"}}`))
+	}))
+	defer srv.Close()
+	stop := errors.New("shutting down")
+
+	calls := 0
+	_, err := New("k", WithBaseURL(srv.URL)).WaitForJob(context.Background(), "j1", &ProcessOptions{
+		PollInterval: time.Millisecond,
+		OnProgressErr: func(*Job) error {
+			if calls++; calls == 2 {
+				return stop
+			}
+			return nil
+		},
+	})
+	if !errors.Is(err, stop) || !strings.Contains(err.Error(), "j1") {
+		t.Errorf("err = %v, want %v wrapped with the job ID", err, stop)
+	}
+	if polls != 2 {
+		t.Errorf("%d polls, want 2", polls)
+	}
+}
+
+func TestPollRequestTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var polls []time.Time
+	hang := map[int]bool{2: true} // 1-based polls that never answer
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls = append(polls, time.Now())
+		n, stuck := len(polls), hang[len(polls)] || hang[0]
+		mu.Unlock()
+		if stuck {
+			<-r.Context().Done()
+			return
+		}
+		if n < 3 {
+			w.Write([]byte(`{"data":{"jobId":"j","status":"VIDEO_PROCESSING"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"jobId":"j","status":"VISION_COMPLETED","processedData":{"length":1}}}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	opts := &ProcessOptions{PollInterval: 30 * time.Millisecond, PollRequestTimeout: 50 * time.Millisecond, Timeout: 5 * time.Second}
+
+	start := time.Now()
+	if _, err := c.WaitForJob(context.Background(), "j", opts); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("recovered after %v, want about one poll request timeout", elapsed)
+	}
+	mu.Lock()
+	if len(polls) != 3 {
+		t.Fatalf("%d polls, want 3", len(polls))
+	}
+	// The hung poll is followed by the usual interval, not an immediate retry
+	if gap := polls[2].Sub(polls[1]); gap < opts.PollRequestTimeout+opts.PollInterval {
+		t.Errorf("poll after the hung one came %v later, want at least %v", gap, opts.PollRequestTimeout+opts.PollInterval)
+	}
+	polls, hang = nil, map[int]bool{0: true} // every poll hangs
+	mu.Unlock()
+
+	opts.MaxPolls = 2
+	if _, err := c.WaitForJob(context.Background(), "j", opts); !errors.Is(err, ErrMaxPollsExceeded) {
+		t.Errorf("err = %v, want ErrMaxPollsExceeded", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(polls) != 2 {
+		t.Errorf("%d hung polls, want 2", len(polls))
+	}
+}
+
+// jobsServer fakes the job listing, lookup, and deletion endpoints over an
+// in-memory set of jobs, kept newest first. Cursors are offsets into the
+// filtered list.
+type jobsServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	jobs         []fakeJob
+	lists        []url.Values // query of each GET /jobs
+	deleted      []string
+	repeatCursor bool // every page names the same next cursor
+}
+
+type fakeJob struct {
+	ID        string    `json:"jobId"`
+	Status    JobStatus `json:"status"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt string    `json:"createdAt"`
+}
+
+// newJobsServer serves n jobs, j1 newest, created a minute apart and with
+// statuses taken from statuses in turn.
+func newJobsServer(t *testing.T, n int, statuses ...JobStatus) *jobsServer {
+	s := &jobsServer{}
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		s.jobs = append(s.jobs, fakeJob{
+			ID:        fmt.Sprintf("j%d", i+1),
+			Status:    statuses[i%len(statuses)],
+			CreatedAt: base.Add(-time.Duration(i) * time.Minute).Format(time.RFC3339),
+		})
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs":
+			s.list(w, r.URL.Query())
+		case r.Method == http.MethodGet && id != r.URL.Path:
+			for _, j := range s.jobs {
+				if j.ID == id {
+					json.NewEncoder(w).Encode(map[string]any{"data": j})
+					return
+				}
+			}
+			http.Error(w, `{"error":"job not found"}`, http.StatusNotFound)
+		case r.Method == http.MethodDelete && id != r.URL.Path:
+			for i, j := range s.jobs {
+				if j.ID == id {
+					s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+					s.deleted = append(s.deleted, id)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			http.Error(w, `{"error":"job not found"}`, http.StatusNotFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+func (s *jobsServer) list(w http.ResponseWriter, q url.Values) {
+	s.lists = append(s.lists, q)
+	var match []fakeJob
+	for _, j := range s.jobs {
+		if st := q.Get("status"); st != "" && string(j.Status) != st {
+			continue
+		}
+		if tag := q.Get("tag"); tag != "" && !containsString(j.Tags, tag) {
+			continue
+		}
+		if before := q.Get("createdBefore"); before != "" && j.CreatedAt >= before {
+			continue
+		}
+		match = append(match, j)
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	off, _ := strconv.Atoi(q.Get("cursor"))
+	end := min(off+limit, len(match))
+	resp := map[string]any{"data": match[min(off, end):end], "total": len(match)}
+	switch {
+	case s.repeatCursor:
+		resp["nextCursor"] = "1"
+	case end < len(match):
+		resp["nextCursor"] = strconv.Itoa(end)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ids returns the IDs of the jobs still stored.
+func (s *jobsServer) ids() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, j := range s.jobs {
+		out = append(out, j.ID)
+	}
+	return out
+}
+
+func TestDeleteJob(t *testing.T) {
+	srv := newJobsServer(t, 3, StatusVisionCompleted)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	if err := c.DeleteJob(ctx, "j2"); err != nil {
+		t.Fatal(err)
+	}
+	page, err := c.ListJobs(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, j := range page.Jobs {
+		listed = append(listed, j.ID)
+	}
+	if want := []string{"j1", "j3"}; !reflect.DeepEqual(listed, want) {
+		t.Errorf("listed %v after deleting j2, want %v", listed, want)
+	}
+	if err := c.DeleteJob(ctx, "j2"); !IsNotFoundError(err) {
+		t.Errorf("deleting j2 again: err = %v, want a not-found error", err)
+	}
+}
+
+func TestRecentResultsCompleteOnly(t *testing.T) {
+	srv := newJobsServer(t, 250, StatusQueued, StatusVisionCompleted, StatusFailed, StatusCompletedNoScenes, StatusVideoProcessing)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	results, err := c.RecentResults(context.Background(), 45, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.JobID)
+	}
+	// Two in every five jobs are complete: j2, j4, j7, j9, ...
+	var want []string
+	for i := 0; len(want) < 45; i++ {
+		if i%5 == 1 || i%5 == 3 {
+			want = append(want, fmt.Sprintf("j%d", i+1))
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results for %v, want %v", got, want)
+	}
+	for _, q := range srv.lists {
+		if q.Has("status") {
+			t.Errorf("listed with status=%q; want completion filtered client-side", q.Get("status"))
+		}
+	}
+	if len(srv.lists) != 2 {
+		t.Errorf("listed %d pages, want 2", len(srv.lists))
+	}
+}
+
+func TestProcessURLManifest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/from-url":
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"PENDING_FETCH"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/j1":
+			// The server downgraded the mode and echoed the callback
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"VISION_COMPLETED","processingMode":"transcript",
+				"callbackUrl":"https://example.com/cb","processedData":{"length":3}}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+	before := time.Now().UTC()
+
+	r, err := c.ProcessURL(context.Background(), "https://cdn.example.com/v.mp4", &ProcessOptions{
+		PollInterval:   time.Millisecond,
+		ProcessingMode: "vision",
+		CallbackURL:    "https://example.com/cb",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := r.Manifest
+	if m == nil {
+		t.Fatal("no manifest")
+	}
+	if m.SDKVersion != version || m.BaseURL != srv.URL || m.SubmittedAt.Before(before.Add(-time.Second)) {
+		t.Errorf("manifest = %+v", m)
+	}
+	if m.Options["url"] != "https://cdn.example.com/v.mp4" || m.Options["processingMode"] != "vision" {
+		t.Errorf("Options = %v, want the request as sent", m.Options)
+	}
+	if want := []string{"processingMode"}; !reflect.DeepEqual(m.Overridden, want) {
+		t.Errorf("Overridden = %v, want %v", m.Overridden, want)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back ProcessingResult
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Manifest == nil || !reflect.DeepEqual(back.Manifest.Overridden, m.Overridden) || !back.Manifest.SubmittedAt.Equal(m.SubmittedAt) {
+		t.Errorf("manifest after a JSON round trip = %+v, want %+v", back.Manifest, m)
+	}
+}
+
+func TestGetJobs(t *testing.T) {
+	srv := newJobsServer(t, 5, StatusVisionCompleted, StatusQueued)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0), WithFetchConcurrency(2))
+	ctx := context.Background()
+
+	jobs, err := c.GetJobs(ctx, []string{"j3", "j1", "j4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, j := range jobs {
+		got = append(got, j.ID+" "+string(j.Status))
+	}
+	if want := []string{"j3 VISION_COMPLETED", "j1 VISION_COMPLETED", "j4 QUEUED"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetJobs = %v, want %v", got, want)
+	}
+
+	jobs, err = c.GetJobs(ctx, []string{"j2", "gone", "j5"})
+	var gerr *GetJobsError
+	if !errors.As(err, &gerr) || len(gerr.Errors) != 3 {
+		t.Fatalf("err = %v, want a *GetJobsError for 3 jobs", err)
+	}
+	if gerr.Errors[0] != nil || gerr.Errors[2] != nil || !IsNotFoundError(gerr.Errors[1]) {
+		t.Errorf("Errors = %v, want only the second to fail, with not found", gerr.Errors)
+	}
+	if jobs[0].ID != "j2" || jobs[1].ID != "" || jobs[2].ID != "j5" {
+		t.Errorf("jobs = %+v, want the loaded jobs in place", jobs)
+	}
+	if !strings.Contains(err.Error(), "1 of 3 jobs failed") {
+		t.Errorf("message = %q", err.Error())
+	}
+}
+
+func TestUpdateJob(t *testing.T) {
+	var patched []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/jobs/j1" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		patched = append(patched, body)
+		if len(patched) > 1 {
+			http.Error(w, `{"error":"job was modified"}`, http.StatusConflict)
+			return
+		}
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"VISION_COMPLETED","displayName":"Keynote",
+			"metadata":{"team":"ingest"},"tags":["conf","2026"]}}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	job, err := c.UpdateJob(ctx, "j1", &JobPatch{DisplayName: "Keynote", Tags: []string{"conf", "2026"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"displayName": "Keynote", "tags": []any{"conf", "2026"}}; !reflect.DeepEqual(patched[0], want) {
+		t.Errorf("sent %v, want %v (empty fields left out)", patched[0], want)
+	}
+	if job.DisplayName != "Keynote" || job.Metadata["team"] != "ingest" || !reflect.DeepEqual(job.Tags, []string{"conf", "2026"}) {
+		t.Errorf("job = %+v", job)
+	}
+	if _, err := c.UpdateJob(ctx, "j1", &JobPatch{DisplayName: "Other"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("concurrent update: err = %v, want ErrConflict", err)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	var mu sync.Mutex
+	cancelled := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/j1/cancel":
+			cancelled = true
+			w.Write([]byte(`{"data":{}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/done/cancel":
+			http.Error(w, `{"error":"job already completed"}`, http.StatusConflict)
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/j1":
+			if cancelled {
+				w.Write([]byte(`{"data":{"jobId":"j1","status":"CANCELLED"}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"VIDEO_PROCESSING"}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	job, err := c.CancelJob(ctx, "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "j1" || !job.IsCancelled() || !job.IsTerminal() {
+		t.Errorf("CancelJob = %+v, want j1 CANCELLED", job)
+	}
+	if _, err := c.WaitForJob(ctx, "j1", &ProcessOptions{PollInterval: time.Millisecond}); !errors.Is(err, ErrJobCancelled) {
+		t.Errorf("waiting on a cancelled job: err = %v, want ErrJobCancelled", err)
+	}
+	if _, err := c.CancelJob(ctx, "done"); !IsConflictError(err) {
+		t.Errorf("cancelling a finished job: err = %v, want a conflict error", err)
+	}
+}
+
+func TestCloneAndWait(t *testing.T) {
+	var mu sync.Mutex
+	var cloneBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/j1/clone":
+			json.NewDecoder(r.Body).Decode(&cloneBody)
+			w.Write([]byte(`{"data":{"jobId":"j2","status":"QUEUED"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/old/clone":
+			http.Error(w, `{"error":"source expired"}`, http.StatusGone)
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/j2":
+			w.Write([]byte(`{"data":{"jobId":"j2","status":"VISION_COMPLETED","processingMode":"vision","processedData":{"length":4}}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	r, err := c.CloneAndWait(ctx, "j1", &CloneOptions{DisplayName: "Rerun", ProcessingMode: "vision"}, &ProcessOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"displayName": "Rerun", "processingMode": "vision"}; !reflect.DeepEqual(cloneBody, want) {
+		t.Errorf("clone body = %v, want %v (empty fields carried over)", cloneBody, want)
+	}
+	if r.JobID != "j2" || r.Duration != 4 {
+		t.Errorf("result = %+v, want the new job j2", r)
+	}
+	if r.Manifest == nil || r.Manifest.Options["displayName"] != "Rerun" || len(r.Manifest.Overridden) != 0 {
+		t.Errorf("manifest = %+v", r.Manifest)
+	}
+
+	if _, err := c.CloneJob(ctx, "old", nil); !errors.Is(err, ErrSourceExpired) {
+		t.Errorf("cloning an expired source: err = %v, want ErrSourceExpired", err)
+	}
+}
+
+func TestRetryJob(t *testing.T) {
+	var mu sync.Mutex
+	var retryBody map[string]any
+	retried := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/j1/retry":
+			json.NewDecoder(r.Body).Decode(&retryBody)
+			retried = true
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"QUEUED"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs/gone/retry":
+			http.Error(w, `{"error":"source purged"}`, http.StatusGone)
+		case r.Method == http.MethodGet && r.URL.Path == "/jobs/j1":
+			if !retried {
+				w.Write([]byte(`{"data":{"jobId":"j1","status":"FAILED","errorMessage":"transient"}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":3}}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	if job, err := c.GetJob(ctx, "j1"); err != nil || job.Status != StatusFailed {
+		t.Fatalf("GetJob = %+v, %v; want the FAILED job", job, err)
+	}
+	r, err := c.RetryAndWait(ctx, "j1", &ProcessOptions{PollInterval: time.Millisecond, ProcessingMode: "vision"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.JobID != "j1" || r.Duration != 3 {
+		t.Errorf("result = %+v", r)
+	}
+	if retryBody["processingMode"] != "vision" {
+		t.Errorf("retry body = %v, want processingMode vision", retryBody)
+	}
+
+	_, err = c.RetryJob(ctx, "gone", nil)
+	if !errors.Is(err, ErrSourcePurged) {
+		t.Errorf("err = %v, want ErrSourcePurged", err)
+	}
+}
+
+func TestSearchJobsByTag(t *testing.T) {
+	srv := newJobsServer(t, 10, StatusVisionCompleted)
+	defer srv.Close()
+	for _, i := range []int{1, 4, 7} {
+		srv.jobs[i].Tags = []string{"other", "conf"}
+	}
+	c := New("k", WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	opts := &ListJobsOptions{Limit: 2}
+	page, err := c.SearchJobsByTag(ctx, "conf", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Tag != "" {
+		t.Errorf("opts.Tag = %q; SearchJobsByTag modified the caller's options", opts.Tag)
+	}
+	ids := func(p *JobPage) []string {
+		var out []string
+		for _, j := range p.Jobs {
+			out = append(out, j.ID)
+		}
+		return out
+	}
+	if got, want := ids(page), []string{"j2", "j5"}; !reflect.DeepEqual(got, want) || !page.HasMore() {
+		t.Fatalf("first page = %v (more %v), want %v and more", got, page.HasMore(), want)
+	}
+	next, err := page.NextPage(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(next), []string{"j8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second page = %v, want %v", got, want)
+	}
+	for _, q := range srv.lists {
+		if q.Get("tag") != "conf" {
+			t.Errorf("listed with tag=%q, want conf on every page", q.Get("tag"))
+		}
+	}
+}
+
+func TestDeleteJobs(t *testing.T) {
+	srv := newJobsServer(t, 8*MaxListJobsLimit+10, StatusFailed, StatusVisionCompleted)
+	defer srv.Close()
+	want, total := 0, len(srv.jobs)
+	for i := range srv.jobs {
+		if i%4 < 2 {
+			srv.jobs[i].Tags = []string{"test"}
+		}
+		if i%4 == 0 { // FAILED and tagged
+			want++
+		}
+	}
+	c := New("k", WithBaseURL(srv.URL))
+	opts := &DeleteJobsOptions{Status: StatusFailed, Tag: "test"}
+	ctx := context.Background()
+
+	dry := *opts
+	dry.DryRun = true
+	n, err := c.DeleteJobs(ctx, &dry)
+	if err != nil || n != want {
+		t.Fatalf("dry run = %d, %v; want %d", n, err, want)
+	}
+	if len(srv.deleted) != 0 {
+		t.Fatalf("dry run deleted %v", srv.deleted)
+	}
+	for _, q := range srv.lists {
+		if q.Get("status") != "FAILED" || q.Get("tag") != "test" {
+			t.Errorf("list query %v, want the status and tag filters sent", q)
+		}
+	}
+
+	srv.lists = nil
+	n, err = c.DeleteJobs(ctx, opts)
+	if err != nil || n != want {
+		t.Fatalf("DeleteJobs = %d, %v; want %d", n, err, want)
+	}
+	if len(srv.lists) < 2 {
+		t.Errorf("%d list requests, want the matches paged through", len(srv.lists))
+	}
+	if left := len(srv.ids()); left != total-want {
+		t.Errorf("%d jobs left, want %d", left, total-want)
+	}
+	if left, _ := c.DeleteJobs(ctx, &dry); left != 0 {
+		t.Errorf("%d matches left after deletion", left)
+	}
+}
+
+func TestNotFoundGracePeriod(t *testing.T) {
+	for _, justCreated := range []bool{true, false} {
+		var mu sync.Mutex
+		polls := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			polls++
+			n := polls
+			mu.Unlock()
+			if n <= 2 {
+				http.Error(w, `{"error":"job not found"}`, http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"data":{"jobId":"j","status":"VISION_COMPLETED","processedData":{"length":1}}}`))
+		}))
+		c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+		_, err := c.WaitForJob(context.Background(), "j", &ProcessOptions{PollInterval: time.Millisecond, JustCreated: justCreated})
+		srv.Close()
+		if justCreated {
+			if err != nil || polls != 3 {
+				t.Errorf("just created: err = %v after %d polls, want success on the third", err, polls)
+			}
+		} else if !IsNotFoundError(err) || polls != 1 {
+			t.Errorf("not just created: err = %v after %d polls, want not found on the first", err, polls)
+		}
+	}
+}
+
+func TestCountJobsByStatus(t *testing.T) {
+	srv := newJobsServer(t, 12, StatusQueued, StatusVideoProcessing, StatusFailed, StatusVisionCompleted, "ARCHIVED")
+	defer srv.Close()
+	want := map[JobStatus]int{}
+	for _, j := range srv.jobs {
+		want[j.Status]++
+	}
+	got, err := New("k", WithBaseURL(srv.URL), WithMaxRetries(0)).CountJobsByStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) != 5 || !reflect.DeepEqual(got, want) {
+		t.Errorf("counts = %v, want %v", got, want)
+	}
+
+	agg := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs/counts" {
+			t.Errorf("unexpected %s %s with an aggregate endpoint", r.Method, r.URL)
+		}
+		w.Write([]byte(`{"data":{"counts":{"QUEUED":3,"ARCHIVED":1,"FAILED":0}}}`))
+	}))
+	defer agg.Close()
+	got, err = New("k", WithBaseURL(agg.URL)).CountJobsByStatus(context.Background())
+	if want := map[JobStatus]int{StatusQueued: 3, "ARCHIVED": 1}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregate counts = %v, %v; want %v", got, err, want)
+	}
+}
+
+func TestRetriesReuseConnection(t *testing.T) {
+	var mu sync.Mutex
+	conns, calls := 0, 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		if n <= 4 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, strings.Repeat("overloaded ", 1000), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"plan":"pro"}}`))
+	}))
+	srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+	tr := &http.Transport{}
+	defer tr.CloseIdleConnections()
+
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(4), WithHTTPClient(&http.Client{Transport: tr}))
+	if _, err := c.GetQuota(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 5 || conns != 1 {
+		t.Errorf("%d requests over %d connections, want 5 over 1", calls, conns)
+	}
+}
@@ -0,0 +1,152 @@
+// Package compat keeps code written for the string job statuses of earlier
+// releases compiling. framequery.JobStatus is now a defined type, so a status
+// no longer assigns to a string variable or passes to strings functions, and
+// CountJobsByStatus keys its counts by JobStatus. Wrapping the client brings
+// back the old shapes for the calls that carry a status:
+//
+//	client := compat.WrapClient(framequery.New("fq_..."))
+//	job, err := client.GetJob(ctx, id)
+//	if strings.HasPrefix(job.Status, "PENDING") { ... } // job.Status is a string
+//
+// Every other method is the wrapped client's own.
+//
+// To migrate off this package, convert at the boundary: string(job.Status)
+// where a string is needed, framequery.JobStatus(s) where a status is. For
+// the common cases:
+//
+//	sed -i -E 's/strings\.(HasPrefix|Contains)\(([A-Za-z_.]+)\.Status,/strings.\1(string(\2.Status),/' *.go
+//	sed -i 's/map\[string\]int/map[framequery.JobStatus]int/' *.go # CountJobsByStatus results
+package compat
+
+import (
+	"context"
+
+	framequery "github.com/framequery/framequery-go"
+)
+
+// Client is a framequery.Client whose job and result calls use string
+// statuses.
+type Client struct {
+	*framequery.Client
+}
+
+// WrapClient returns c with the string-status methods of this package.
+func WrapClient(c *framequery.Client) *Client {
+	return &Client{Client: c}
+}
+
+// Job is a framequery.Job with Status as a plain string.
+type Job struct {
+	*framequery.Job
+	Status string
+}
+
+// FromJob adapts j. It returns nil for a nil j.
+func FromJob(j *framequery.Job) *Job {
+	if j == nil {
+		return nil
+	}
+	return &Job{Job: j, Status: string(j.Status)}
+}
+
+// ProcessingResult is a framequery.ProcessingResult with Status as a plain
+// string.
+type ProcessingResult struct {
+	*framequery.ProcessingResult
+	Status string
+}
+
+// FromResult adapts r. It returns nil for a nil r.
+func FromResult(r *framequery.ProcessingResult) *ProcessingResult {
+	if r == nil {
+		return nil
+	}
+	return &ProcessingResult{ProcessingResult: r, Status: string(r.Status)}
+}
+
+// ListJobsOptions is the v0 framequery.ListJobsOptions, with Status as a
+// plain string.
+type ListJobsOptions struct {
+	Limit  int
+	Cursor string
+	Status string
+}
+
+func (o *ListJobsOptions) typed() *framequery.ListJobsOptions {
+	if o == nil {
+		return nil
+	}
+	return &framequery.ListJobsOptions{Limit: o.Limit, Cursor: o.Cursor, Status: framequery.JobStatus(o.Status)}
+}
+
+// JobPage is a page of string-status jobs.
+type JobPage struct {
+	Jobs       []Job
+	NextCursor string
+}
+
+// HasMore reports whether another page is available.
+func (p *JobPage) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+// GetJob is framequery.Client.GetJob.
+func (c *Client) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	j, err := c.Client.GetJob(ctx, jobID)
+	return FromJob(j), err
+}
+
+// ListJobs is framequery.Client.ListJobs.
+func (c *Client) ListJobs(ctx context.Context, opts *ListJobsOptions) (*JobPage, error) {
+	p, err := c.Client.ListJobs(ctx, opts.typed())
+	if err != nil {
+		return nil, err
+	}
+	page := &JobPage{Jobs: make([]Job, len(p.Jobs)), NextCursor: p.NextCursor}
+	for i := range p.Jobs {
+		page.Jobs[i] = *FromJob(&p.Jobs[i])
+	}
+	return page, nil
+}
+
+// CountJobs is framequery.Client.CountJobs.
+func (c *Client) CountJobs(ctx context.Context, opts *ListJobsOptions) (int, error) {
+	return c.Client.CountJobs(ctx, opts.typed())
+}
+
+// CountJobsByStatus is framequery.Client.CountJobsByStatus, keyed by string.
+func (c *Client) CountJobsByStatus(ctx context.Context) (map[string]int, error) {
+	counts, err := c.Client.CountJobsByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(counts))
+	for st, n := range counts {
+		out[string(st)] = n
+	}
+	return out, nil
+}
+
+// Process is framequery.Client.Process.
+func (c *Client) Process(ctx context.Context, path string, opts *framequery.ProcessOptions) (*ProcessingResult, error) {
+	r, err := c.Client.Process(ctx, path, opts)
+	return FromResult(r), err
+}
+
+// ProcessURL is framequery.Client.ProcessURL.
+func (c *Client) ProcessURL(ctx context.Context, videoURL string, opts *framequery.ProcessOptions) (*ProcessingResult, error) {
+	r, err := c.Client.ProcessURL(ctx, videoURL, opts)
+	return FromResult(r), err
+}
+
+// WaitForJob is framequery.Client.WaitForJob.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, opts *framequery.ProcessOptions) (*ProcessingResult, error) {
+	r, err := c.Client.WaitForJob(ctx, jobID, opts)
+	return FromResult(r), err
+}
+
+// GetResult is framequery.Client.GetResult.
+func (c *Client) GetResult(ctx context.Context, jobID string) (*ProcessingResult, error) {
+	r, err := c.Client.GetResult(ctx, jobID)
+	return FromResult(r), err
+}
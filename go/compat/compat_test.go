@@ -0,0 +1,122 @@
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	framequery "github.com/framequery/framequery-go"
+)
+
+// corpus holds job payloads with known, undocumented, and missing statuses.
+var corpus = []string{
+	`{"jobId":"j1","status":"QUEUED","originalFilename":"a.mp4","createdAt":"2026-03-01T10:00:00Z","estimatedCompletionTimeSeconds":30}`,
+	`{"jobId":"j2","status":"VISION_COMPLETED","originalFilename":"b.mp4","audioTrackCount":2,"audioTracksCompleted":2,"audioTrackNames":["en","fr"],"processedData":{"length":12}}`,
+	`{"jobId":"j3","status":"INGEST_FAILED_TRANSCODE","errorMessage":"codec"}`,
+	`{"jobId":"j4","status":"SOME_NEW_STAGE"}`,
+	`{"jobId":"j5"}`,
+}
+
+// v0Job is how earlier releases decoded a job.
+type v0Job struct {
+	ID                   string   `json:"jobId"`
+	Status               string   `json:"status"`
+	Filename             string   `json:"originalFilename"`
+	CreatedAt            string   `json:"createdAt"`
+	ETASeconds           float64  `json:"estimatedCompletionTimeSeconds"`
+	AudioTrackCount      *int     `json:"audioTrackCount"`
+	AudioTracksCompleted *int     `json:"audioTracksCompleted"`
+	AudioTrackNames      []string `json:"audioTrackNames"`
+}
+
+func newCorpusServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/jobs":
+			w.Write([]byte(`{"data":[` + strings.Join(corpus, ",") + `]}`))
+		case r.URL.Path == "/jobs/counts":
+			w.Write([]byte(`{"data":{"QUEUED":2,"SOME_NEW_STAGE":1}}`))
+		case strings.HasPrefix(r.URL.Path, "/jobs/j"):
+			for _, body := range corpus {
+				if strings.Contains(body, `"jobId":"`+strings.TrimPrefix(r.URL.Path, "/jobs/")+`"`) {
+					w.Write([]byte(`{"data":` + body + `}`))
+					return
+				}
+			}
+			http.NotFound(w, r)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestJobsMatchV0(t *testing.T) {
+	srv := newCorpusServer(t)
+	defer srv.Close()
+	c := WrapClient(framequery.New("k", framequery.WithBaseURL(srv.URL)))
+	ctx := context.Background()
+
+	page, err := c.ListJobs(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Jobs) != len(corpus) {
+		t.Fatalf("%d jobs, want %d", len(page.Jobs), len(corpus))
+	}
+	for i, body := range corpus {
+		var want v0Job
+		if err := json.Unmarshal([]byte(body), &want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.GetJob(ctx, want.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, j := range []*Job{got, &page.Jobs[i]} {
+			v0 := v0Job{j.ID, j.Status, j.Filename, j.CreatedAt, j.ETASeconds, j.AudioTrackCount, j.AudioTracksCompleted, j.AudioTrackNames}
+			if !reflect.DeepEqual(v0, want) {
+				t.Errorf("job %s = %+v, want %+v", want.ID, v0, want)
+			}
+		}
+	}
+}
+
+func TestResultAndCounts(t *testing.T) {
+	srv := newCorpusServer(t)
+	defer srv.Close()
+	c := WrapClient(framequery.New("k", framequery.WithBaseURL(srv.URL)))
+	ctx := context.Background()
+
+	r, err := c.GetResult(ctx, "j2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var status string = r.Status // compiles only while Status is a string
+	if status != "VISION_COMPLETED" || r.Duration != 12 {
+		t.Errorf("result status %q, duration %v", status, r.Duration)
+	}
+	if _, err := c.GetResult(ctx, "j1"); err == nil {
+		t.Error("GetResult on a queued job: want an error")
+	}
+
+	counts, err := c.CountJobsByStatus(ctx)
+	if want := map[string]int{"QUEUED": 2, "SOME_NEW_STAGE": 1}; err != nil || !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, %v; want %v", counts, err, want)
+	}
+}
+
+func TestMigrationRecipe(t *testing.T) {
+	// The first sed recipe in the package doc, in RE2 syntax
+	re := regexp.MustCompile(`strings\.(HasPrefix|Contains)\(([A-Za-z_.]+)\.Status,`)
+	src := `if strings.HasPrefix(job.Status, "PENDING") || strings.Contains(resp.Job.Status, "FAILED") {`
+	want := `if strings.HasPrefix(string(job.Status), "PENDING") || strings.Contains(string(resp.Job.Status), "FAILED") {`
+	if got := re.ReplaceAllString(src, "strings.$1(string($2.Status),"); got != want {
+		t.Errorf("recipe gave\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,137 @@
+package framequery
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithDebug logs every HTTP request and response (method, URL, headers, body)
+// to w. The Authorization header and the query strings of presigned URLs are
+// redacted. Only JSON and text bodies are dumped, so uploads and streams pass
+// through unread. Applied after all other options, so it wraps any custom
+// transport.
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) { c.debugWriter = w }
+}
+
+// debugTransport is an http.RoundTripper that dumps traffic to w.
+type debugTransport struct {
+	next http.RoundTripper
+	w    io.Writer
+	mu   sync.Mutex
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && isDumpable(req.Header) {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		// A RoundTripper must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(b)), nil }
+	}
+
+	resp, err := t.next.RoundTrip(req)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, redactURL(req))
+	writeHeaders(&buf, "> ", req.Header)
+	switch {
+	case reqBody != nil:
+		fmt.Fprintf(&buf, ">\n%s\n", signedQuery.ReplaceAll(reqBody, []byte("$1?***")))
+	case req.Body != nil:
+		fmt.Fprintf(&buf, ">\n<%s body not logged, %d bytes>\n", req.Header.Get("Content-Type"), req.ContentLength)
+	}
+
+	if err != nil {
+		fmt.Fprintf(&buf, "< error: %v\n\n", err)
+		t.write(buf.Bytes())
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "< %s\n", resp.Status)
+	writeHeaders(&buf, "< ", resp.Header)
+	if !isDumpable(resp.Header) {
+		fmt.Fprintf(&buf, "<\n<%s body not logged, %d bytes>\n\n", resp.Header.Get("Content-Type"), resp.ContentLength)
+		t.write(buf.Bytes())
+		return resp, nil
+	}
+
+	respBody, readErr := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	fmt.Fprintf(&buf, "<\n%s\n\n", signedQuery.ReplaceAll(respBody, []byte("$1?***")))
+	t.write(buf.Bytes())
+
+	if readErr != nil {
+		return nil, readErr
+	}
+	return resp, nil
+}
+
+func (t *debugTransport) write(b []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.w.Write(b)
+}
+
+// isDumpable reports whether a body with these headers is JSON or text that
+// can be read whole: not a file upload, multipart form, or event stream.
+func isDumpable(h http.Header) bool {
+	mt, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	switch {
+	case mt == "application/json", strings.HasSuffix(mt, "+json"):
+		return true
+	case mt == "text/event-stream":
+		return false
+	}
+	return strings.HasPrefix(mt, "text/")
+}
+
+// signedQuery matches a URL in a body with its query string, which for the
+// presigned URLs the API hands out holds the signature.
+var signedQuery = regexp.MustCompile(`(https?://[^\s"'?]+)\?[^\s"']+`)
+
+// redactURL returns req's URL, with the query hidden for requests that don't
+// carry the API key: those go to presigned URLs, where the query is the
+// credential.
+func redactURL(req *http.Request) string {
+	if req.URL.RawQuery == "" || req.Header.Get("Authorization") != "" {
+		return req.URL.String()
+	}
+	u := *req.URL
+	u.RawQuery = ""
+	return u.String() + "?***"
+}
+
+func writeHeaders(w io.Writer, prefix string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			if k == "Authorization" {
+				v = "Bearer ***"
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, k, v)
+		}
+	}
+}
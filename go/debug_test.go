@@ -0,0 +1,156 @@
+package framequery
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubTransport answers every request with resp, after checking the body.
+type stubTransport struct {
+	check func(*http.Request)
+	resp  func() *http.Response
+}
+
+func (s stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.check(req)
+	return s.resp(), nil
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDebugLeavesRequestUnmodified(t *testing.T) {
+	var buf bytes.Buffer
+	var sent string
+	dt := &debugTransport{w: &buf, next: stubTransport{
+		check: func(req *http.Request) {
+			b, _ := io.ReadAll(req.Body)
+			sent = string(b)
+		},
+		resp: func() *http.Response { return jsonResponse(`{"data":{}}`) },
+	}}
+	body := io.NopCloser(strings.NewReader(`{"url":"https://cdn.example.com/v.mp4"}`))
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example/jobs", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	if _, err := dt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Body != body {
+		t.Error("RoundTrip replaced the caller's req.Body")
+	}
+	if sent != `{"url":"https://cdn.example.com/v.mp4"}` {
+		t.Errorf("upstream got body %q", sent)
+	}
+	if !strings.Contains(buf.String(), "cdn.example.com/v.mp4") {
+		t.Errorf("log =\n%s", buf.String())
+	}
+}
+
+func TestDebugSkipsUploadAndStreamBodies(t *testing.T) {
+	upload := strings.NewReader("--boundary\r\n...video bytes...")
+	var buf bytes.Buffer
+	dt := &debugTransport{w: &buf, next: stubTransport{
+		check: func(req *http.Request) {
+			if req.Body == nil {
+				t.Fatal("request body dropped")
+			}
+			if n, _ := io.Copy(io.Discard, req.Body); n != int64(len("--boundary\r\n...video bytes...")) {
+				t.Errorf("upstream read %d bytes; debug consumed the body", n)
+			}
+		},
+		resp: func() *http.Response {
+			pr, _ := io.Pipe() // never written: reading would hang
+			return &http.Response{Status: "200 OK", StatusCode: 200, ContentLength: -1, Header: http.Header{"Content-Type": {"text/event-stream"}}, Body: pr}
+		},
+	}}
+	for _, ct := range []string{"multipart/form-data; boundary=boundary", "video/mp4", "application/octet-stream"} {
+		upload.Seek(0, io.SeekStart)
+		buf.Reset()
+		req, _ := http.NewRequest(http.MethodPost, "https://storage.example/upload", io.NopCloser(upload))
+		req.Header.Set("Content-Type", ct)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if _, err := dt.RoundTrip(req); err != nil {
+				t.Error(err)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s: RoundTrip blocked reading a streaming response", ct)
+		}
+		if strings.Contains(buf.String(), "video bytes") || !strings.Contains(buf.String(), "body not logged") {
+			t.Errorf("%s: log =\n%s", ct, buf.String())
+		}
+	}
+}
+
+func TestDebugRedactsPresignedURLs(t *testing.T) {
+	var buf bytes.Buffer
+	dt := &debugTransport{w: &buf, next: stubTransport{
+		check: func(*http.Request) {},
+		resp: func() *http.Response {
+			return jsonResponse(`{"data":{"uploadUrl":"https://bucket.s3.amazonaws.com/v.mp4?X-Amz-Signature=abc123\u0026X-Amz-Credential=AKIA"}}`)
+		},
+	}}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.framequery.com/v1/api/jobs?limit=5", strings.NewReader(`{"fileName":"v.mp4"}`))
+	req.Header.Set("Authorization", "Bearer fq_secret")
+	req.Header.Set("Content-Type", "application/json")
+	if _, err := dt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	req, _ = http.NewRequest(http.MethodPut, "https://bucket.s3.amazonaws.com/v.mp4?X-Amz-Signature=abc123", nil)
+	if _, err := dt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	log := buf.String()
+	for _, secret := range []string{"fq_secret", "abc123", "AKIA"} {
+		if strings.Contains(log, secret) {
+			t.Errorf("log contains %q:\n%s", secret, log)
+		}
+	}
+	for _, want := range []string{"/jobs?limit=5", `"fileName":"v.mp4"`, "https://bucket.s3.amazonaws.com/v.mp4?***"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log missing %q:\n%s", want, log)
+		}
+	}
+}
+
+func TestLoggingInterceptorRedactsPresignedURLs(t *testing.T) {
+	var buf bytes.Buffer
+	next := stubTransport{check: func(*http.Request) {}, resp: func() *http.Response { return jsonResponse(`{}`) }}
+	log := LoggingInterceptor(&buf)
+
+	put, _ := http.NewRequest(http.MethodPut, "https://bucket.s3.amazonaws.com/v.mp4?X-Amz-Signature=abc123&X-Amz-Credential=AKIA", strings.NewReader("video"))
+	if _, err := log.RoundTrip(put, next); err != nil {
+		t.Fatal(err)
+	}
+	api, _ := http.NewRequest(http.MethodGet, "https://api.example/jobs?limit=5", nil)
+	api.Header.Set("Authorization", "Bearer k")
+	if _, err := log.RoundTrip(api, next); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "abc123") || strings.Contains(out, "AKIA") {
+		t.Errorf("signature logged:\n%s", out)
+	}
+	if !strings.Contains(out, "PUT https://bucket.s3.amazonaws.com/v.mp4?*** 200") || !strings.Contains(out, "GET https://api.example/jobs?limit=5 200") {
+		t.Errorf("log =\n%s", out)
+	}
+}
@@ -0,0 +1,124 @@
+package framequery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	dedupTTL        = time.Hour
+	dedupMaxEntries = 1024
+)
+
+// WithUploadDedup makes concurrent Upload/Process calls for the same file
+// (absolute path, size, and modification time) share one job. The first call
+// creates the job; later calls wait for it and get the same Job back, with the
+// first call's options. Entries expire after an hour or once the job is
+// observed in a terminal state.
+func WithUploadDedup(enabled bool) Option {
+	return func(c *Client) {
+		if enabled {
+			c.dedup = newUploadDedup()
+		} else {
+			c.dedup = nil
+		}
+	}
+}
+
+type uploadDedup struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	done    chan struct{}
+	job     *Job
+	err     error
+	created time.Time
+}
+
+func newUploadDedup() *uploadDedup {
+	return &uploadDedup{entries: map[string]*dedupEntry{}}
+}
+
+// dedupKey identifies a file on disk by absolute path, size, and mtime.
+func dedupKey(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s|%d|%d", abs, fi.Size(), fi.ModTime().UnixNano()), nil
+}
+
+// acquire returns the entry for key and whether the caller must perform the
+// upload. A nil entry means the table is full and the caller should upload
+// without deduplication.
+func (d *uploadDedup) acquire(key string) (*dedupEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range d.entries {
+		if now.Sub(e.created) > dedupTTL {
+			delete(d.entries, k)
+		}
+	}
+	if e, ok := d.entries[key]; ok {
+		return e, false
+	}
+	if len(d.entries) >= dedupMaxEntries {
+		return nil, true
+	}
+	e := &dedupEntry{done: make(chan struct{}), created: now}
+	d.entries[key] = e
+	return e, true
+}
+
+// finish records the upload outcome. Failed uploads are removed so the next
+// caller retries.
+func (d *uploadDedup) finish(key string, e *dedupEntry, job *Job, err error) {
+	d.mu.Lock()
+	e.job, e.err = job, err
+	if err != nil {
+		delete(d.entries, key)
+	}
+	d.mu.Unlock()
+	close(e.done)
+}
+
+// forgetJob drops entries for a job that reached a terminal state.
+func (d *uploadDedup) forgetJob(jobID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, e := range d.entries {
+		select {
+		case <-e.done:
+			if e.job != nil && e.job.ID == jobID {
+				delete(d.entries, k)
+			}
+		default:
+		}
+	}
+}
+
+// wait blocks until the leading upload finishes and returns a copy of its Job.
+func (e *dedupEntry) wait(ctx context.Context) (*Job, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-e.done:
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	job := *e.job
+	return &job, nil
+}
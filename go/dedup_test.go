@@ -0,0 +1,135 @@
+package framequery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// dedupServer counts job-creation POSTs, each of which takes delay, and
+// reports every job as VISION_COMPLETED.
+type dedupServer struct {
+	*httptest.Server
+	creates atomic.Int32
+}
+
+func newDedupServer(delay time.Duration) *dedupServer {
+	s := &dedupServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs":
+			n := s.creates.Add(1)
+			time.Sleep(delay)
+			fmt.Fprintf(w, `{"data":{"jobId":"j%d","uploadUrl":%q}}`, n, s.URL+"/put")
+		case r.Method == http.MethodPut:
+		case r.Method == http.MethodGet:
+			fmt.Fprintf(w, `{"data":{"jobId":%q,"status":"VISION_COMPLETED","processedData":{"length":1}}}`, r.URL.Path[len("/jobs/"):])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+func TestUploadDedupConcurrent(t *testing.T) {
+	srv := newDedupServer(100 * time.Millisecond)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithUploadDedup(true))
+	path := writeUploadFile(t, uploadContent)
+
+	ids := make([]string, 8)
+	var wg sync.WaitGroup
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job, err := c.Upload(context.Background(), path, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids[i] = job.ID
+		}()
+	}
+	wg.Wait()
+	if n := srv.creates.Load(); n != 1 {
+		t.Errorf("%d jobs created, want 1", n)
+	}
+	for i, id := range ids {
+		if id != "j1" {
+			t.Errorf("call %d got job %q, want j1", i, id)
+		}
+	}
+}
+
+func TestUploadDedupExpires(t *testing.T) {
+	srv := newDedupServer(0)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithUploadDedup(true))
+	path := writeUploadFile(t, uploadContent)
+	ctx := context.Background()
+
+	if _, err := c.Upload(ctx, path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if job, err := c.Upload(ctx, path, nil); err != nil || job.ID != "j1" {
+		t.Fatalf("second upload = %v, %v; want j1 shared", job, err)
+	}
+	c.dedup.mu.Lock()
+	for _, e := range c.dedup.entries {
+		e.created = time.Now().Add(-dedupTTL - time.Second)
+	}
+	c.dedup.mu.Unlock()
+	if job, err := c.Upload(ctx, path, nil); err != nil || job.ID != "j2" {
+		t.Errorf("upload after the TTL = %v, %v; want a new job j2", job, err)
+	}
+}
+
+func TestUploadDedupForgetsTerminalJob(t *testing.T) {
+	srv := newDedupServer(0)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithUploadDedup(true))
+	path := writeUploadFile(t, uploadContent)
+	ctx := context.Background()
+
+	if _, err := c.Upload(ctx, path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.WaitForJob(ctx, "j1", &ProcessOptions{PollInterval: time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.dedup.entries); n != 0 {
+		t.Errorf("%d entries after j1 completed, want 0", n)
+	}
+	if job, err := c.Upload(ctx, path, nil); err != nil || job.ID != "j2" {
+		t.Errorf("upload after completion = %v, %v; want a new job j2", job, err)
+	}
+}
+
+func TestUploadDedupBound(t *testing.T) {
+	srv := newDedupServer(0)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithUploadDedup(true))
+	path := writeUploadFile(t, uploadContent)
+	ctx := context.Background()
+
+	for i := range dedupMaxEntries {
+		e := &dedupEntry{done: make(chan struct{}), job: &Job{ID: fmt.Sprintf("old%d", i)}, created: time.Now()}
+		close(e.done)
+		c.dedup.entries[fmt.Sprintf("other%d", i)] = e
+	}
+	for _, want := range []string{"j1", "j2"} {
+		job, err := c.Upload(ctx, path, nil)
+		if err != nil || job.ID != want {
+			t.Fatalf("upload with a full table = %v, %v; want %s, not deduplicated", job, err, want)
+		}
+	}
+	if n := len(c.dedup.entries); n != dedupMaxEntries {
+		t.Errorf("%d entries, want the bound %d", n, dedupMaxEntries)
+	}
+}
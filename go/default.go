@@ -0,0 +1,129 @@
+package framequery
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+// The default client behind the package-level functions. It is created on
+// first use, never at import.
+var (
+	defaultMu     sync.Mutex
+	defaultClient *Client
+)
+
+// DefaultClient returns the client the package-level functions use, creating
+// it from FRAMEQUERY_API_KEY on first call. It returns ErrNoAPIKey if the
+// variable is unset and SetDefaultClient hasn't been called; a later call
+// tries again.
+func DefaultClient() (*Client, error) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultClient == nil {
+		key := os.Getenv("FRAMEQUERY_API_KEY")
+		if key == "" {
+			return nil, ErrNoAPIKey
+		}
+		defaultClient = New(key)
+	}
+	return defaultClient, nil
+}
+
+// SetDefaultClient replaces the client the package-level functions use. Nil
+// goes back to creating one from FRAMEQUERY_API_KEY on next use.
+func SetDefaultClient(c *Client) {
+	defaultMu.Lock()
+	defaultClient = c
+	defaultMu.Unlock()
+}
+
+// Process calls Process on the default client.
+func Process(ctx context.Context, path string, opts *ProcessOptions) (*ProcessingResult, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Process(ctx, path, opts)
+}
+
+// ProcessURL calls ProcessURL on the default client.
+func ProcessURL(ctx context.Context, videoURL string, opts *ProcessOptions) (*ProcessingResult, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ProcessURL(ctx, videoURL, opts)
+}
+
+// Upload calls Upload on the default client.
+func Upload(ctx context.Context, path string, opts *UploadOptions) (*Job, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.Upload(ctx, path, opts)
+}
+
+// SubmitURL calls SubmitURL on the default client.
+func SubmitURL(ctx context.Context, videoURL string, opts *UploadOptions) (*Job, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.SubmitURL(ctx, videoURL, opts)
+}
+
+// GetJob calls GetJob on the default client.
+func GetJob(ctx context.Context, jobID string) (*Job, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetJob(ctx, jobID)
+}
+
+// GetResult calls GetResult on the default client.
+func GetResult(ctx context.Context, jobID string) (*ProcessingResult, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetResult(ctx, jobID)
+}
+
+// WaitForJob calls WaitForJob on the default client.
+func WaitForJob(ctx context.Context, jobID string, opts *ProcessOptions) (*ProcessingResult, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitForJob(ctx, jobID, opts)
+}
+
+// ListJobs calls ListJobs on the default client.
+func ListJobs(ctx context.Context, opts *ListJobsOptions) (*JobPage, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.ListJobs(ctx, opts)
+}
+
+// CancelJob calls CancelJob on the default client.
+func CancelJob(ctx context.Context, jobID string) (*Job, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.CancelJob(ctx, jobID)
+}
+
+// GetQuota calls GetQuota on the default client.
+func GetQuota(ctx context.Context) (*Quota, error) {
+	c, err := DefaultClient()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetQuota(ctx)
+}
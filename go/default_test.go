@@ -0,0 +1,57 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestDefaultClient(t *testing.T) {
+	if defaultClient != nil {
+		t.Fatal("default client exists before first use")
+	}
+	t.Cleanup(func() { SetDefaultClient(nil) })
+
+	t.Setenv("FRAMEQUERY_API_KEY", "")
+	if _, err := GetQuota(context.Background()); !errors.Is(err, ErrNoAPIKey) {
+		t.Fatalf("no key: err = %v, want ErrNoAPIKey", err)
+	}
+
+	t.Setenv("FRAMEQUERY_API_KEY", "fq_env")
+	clients := make([]*Client, 16)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], _ = DefaultClient()
+		}(i)
+	}
+	wg.Wait()
+	for _, c := range clients {
+		if c == nil || c != clients[0] {
+			t.Fatal("concurrent first calls created different clients")
+		}
+	}
+	if clients[0].apiKey != "fq_env" {
+		t.Errorf("apiKey = %q", clients[0].apiKey)
+	}
+
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"QUEUED"}}`))
+	}))
+	defer srv.Close()
+	SetDefaultClient(New("fq_test", WithBaseURL(srv.URL)))
+	job, err := GetJob(context.Background(), "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "j1" || auth != "Bearer fq_test" {
+		t.Errorf("job %+v sent with %q", job, auth)
+	}
+}
@@ -0,0 +1,122 @@
+package framequery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Result formats for DeliverResult.
+const (
+	FormatJSON = "json" // the full result, as ProcessingResult.MarshalJSON
+	FormatSRT  = "srt"
+	FormatVTT  = "vtt"
+	FormatText = "txt" // FullTranscript
+)
+
+// ExportSink is caller-owned storage, such as a bucket, that DeliverResult
+// writes to. Names are slash-separated object keys.
+type ExportSink interface {
+	Exists(ctx context.Context, name string) (bool, error)
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// DeliverResult writes a completed job's result to sink as {jobID}/result.{format}
+// for each format (default: json). A {jobID}/.delivered marker is written last;
+// if it already exists, DeliverResult does nothing, so re-runs are safe. Each
+// write is retried up to the client's max retries.
+func (c *Client) DeliverResult(ctx context.Context, jobID string, sink ExportSink, formats []string) error {
+	marker := path.Join(jobID, ".delivered")
+	if done, err := sink.Exists(ctx, marker); err != nil {
+		return fmt.Errorf("framequery: check delivery marker: %w", err)
+	} else if done {
+		return nil
+	}
+
+	result, err := c.GetResult(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if len(formats) == 0 {
+		formats = []string{FormatJSON}
+	}
+	for _, f := range formats {
+		data, err := encodeResult(result, f)
+		if err != nil {
+			return err
+		}
+		if err := c.putWithRetry(ctx, sink, path.Join(jobID, "result."+f), data); err != nil {
+			return err
+		}
+	}
+	stamp := []byte(time.Now().UTC().Format(time.RFC3339) + "\n")
+	return c.putWithRetry(ctx, sink, marker, stamp)
+}
+
+func encodeResult(r *ProcessingResult, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJSON:
+		return json.Marshal(r)
+	case FormatSRT:
+		err = r.WriteSRT(&buf)
+	case FormatVTT:
+		err = r.WriteVTT(&buf)
+	case FormatText:
+		buf.WriteString(r.FullTranscript())
+	default:
+		return nil, fmt.Errorf("framequery: unknown result format %q", format)
+	}
+	return buf.Bytes(), err
+}
+
+func (c *Client) putWithRetry(ctx context.Context, sink ExportSink, name string, data []byte) error {
+	var err error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.backoff(attempt - 1)):
+			}
+		}
+		if err = sink.Put(ctx, name, data); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("framequery: deliver %s: %w", name, err)
+}
+
+// DirSink is an ExportSink writing files under a local directory.
+type DirSink string
+
+// Exists reports whether name exists under the directory.
+func (d DirSink) Exists(_ context.Context, name string) (bool, error) {
+	_, err := os.Stat(filepath.Join(string(d), filepath.FromSlash(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Put writes data to name, creating parent directories. The file is written
+// to a temporary name and renamed, so readers never see a partial result.
+func (d DirSink) Put(_ context.Context, name string, data []byte) error {
+	dst := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
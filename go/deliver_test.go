@@ -0,0 +1,128 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func newDeliverServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs/j1" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		fetches.Add(1)
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":4,
+			"transcript":[{"StartTime":0,"EndTime":2,"Text":"Hello"},{"StartTime":2,"EndTime":4,"Text":"there."}]}}}`))
+	}))
+	return srv, &fetches
+}
+
+func TestDeliverResultDirSink(t *testing.T) {
+	srv, fetches := newDeliverServer(t)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if err := c.DeliverResult(ctx, "j1", DirSink(dir), []string{FormatJSON, FormatSRT, FormatText}); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(dir, "j1", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	if got := read("result.txt"); got != "Hello there." {
+		t.Errorf("result.txt = %q", got)
+	}
+	if got := read("result.srt"); !strings.HasPrefix(got, "1\n00:00:00,000 --> 00:00:02,000\nHello\n") {
+		t.Errorf("result.srt = %q", got)
+	}
+	if got := read("result.json"); !strings.Contains(got, `"jobId":"j1"`) {
+		t.Errorf("result.json = %q", got)
+	}
+	if read(".delivered") == "" {
+		t.Error("empty delivery marker")
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "j1", "*.tmp"))
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+
+	// A re-run sees the marker and fetches nothing
+	if err := c.DeliverResult(ctx, "j1", DirSink(dir), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched the result %d times, want 1", n)
+	}
+}
+
+// flakySink is an in-memory ExportSink whose first failures Puts fail.
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	puts     []string
+	objects  map[string][]byte
+}
+
+func (s *flakySink) Exists(_ context.Context, name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.objects[name]
+	return ok, nil
+}
+
+func (s *flakySink) Put(_ context.Context, name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.puts = append(s.puts, name)
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("bucket unavailable")
+	}
+	s.objects[name] = data
+	return nil
+}
+
+func TestDeliverResultRetriesAndMarksLast(t *testing.T) {
+	srv, _ := newDeliverServer(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	sink := &flakySink{failures: 1, objects: map[string][]byte{}}
+	if err := New("k", WithBaseURL(srv.URL), WithMaxRetries(1)).DeliverResult(ctx, "j1", sink, []string{FormatVTT}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "j1/result.vtt j1/result.vtt j1/.delivered"; strings.Join(sink.puts, " ") != want {
+		t.Errorf("puts = %v, want %s", sink.puts, want)
+	}
+
+	sink = &flakySink{failures: 2, objects: map[string][]byte{}}
+	err := New("k", WithBaseURL(srv.URL), WithMaxRetries(1)).DeliverResult(ctx, "j1", sink, nil)
+	if err == nil || !strings.Contains(err.Error(), "bucket unavailable") {
+		t.Errorf("err = %v, want the sink's error after retries", err)
+	}
+	if _, ok := sink.objects["j1/.delivered"]; ok {
+		t.Error("marker written after a failed delivery")
+	}
+
+	sink = &flakySink{objects: map[string][]byte{}}
+	if err := New("k", WithBaseURL(srv.URL)).DeliverResult(ctx, "j1", sink, []string{"docx"}); err == nil {
+		t.Error("unknown format delivered")
+	}
+	if len(sink.puts) != 0 {
+		t.Errorf("puts = %v, want none for an unknown format", sink.puts)
+	}
+}
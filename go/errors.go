@@ -1,41 +1,347 @@
 package framequery
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
-// Error is an API error. StatusCode is 0 for non-HTTP errors (e.g. job failure).
+// Sentinel errors for errors.Is. An *Error matches the sentinel for its status code:
+//
+//	if errors.Is(err, framequery.ErrJobNotFound) { ... }
+//
+// Any 404 matches ErrNotFound; ErrJobNotFound only matches one for a job,
+// not for endpoints such as /quota or /jobs/counts.
+var (
+	ErrUnauthorized  = errors.New("framequery: unauthorized")
+	ErrForbidden     = errors.New("framequery: forbidden")
+	ErrNotFound      = errors.New("framequery: not found")
+	ErrJobNotFound   = errors.New("framequery: job not found")
+	ErrConflict      = errors.New("framequery: conflict")
+	ErrRateLimited   = errors.New("framequery: rate limited")
+	ErrSourcePurged  = errors.New("framequery: source no longer available")
+	ErrSourceExpired = ErrSourcePurged // alias, returned by CloneJob
+
+	// ErrJobCancelled is returned (wrapped) when a polled job ends up CANCELLED.
+	ErrJobCancelled = errors.New("framequery: job cancelled")
+
+	// ErrJobNotComplete matches a *JobNotCompleteError.
+	ErrJobNotComplete = errors.New("framequery: job not complete")
+
+	// ErrJobFailed matches a *JobFailedError.
+	ErrJobFailed = errors.New("framequery: job failed")
+
+	// ErrResultExpired matches a *ResultExpiredError.
+	ErrResultExpired = errors.New("framequery: result expired")
+
+	// ErrResultNotReady matches a *ResultNotReadyError.
+	ErrResultNotReady = errors.New("framequery: result not ready")
+
+	// ErrMaxPollsExceeded is returned (wrapped) when a job is still running
+	// after ProcessOptions.MaxPolls polls.
+	ErrMaxPollsExceeded = errors.New("framequery: max polls exceeded")
+
+	// ErrMaxPagesExceeded is returned (wrapped) by ForEachJobPage when pages
+	// remain after ListJobsOptions.MaxPages.
+	ErrMaxPagesExceeded = errors.New("framequery: max pages exceeded")
+
+	// ErrNoMorePages is returned by JobPage.NextPage on the last page.
+	ErrNoMorePages = errors.New("framequery: no more pages")
+
+	// ErrInsufficientQuota is returned by ReserveCredits when the account's
+	// available hours, less outstanding reservations, can't cover the request.
+	ErrInsufficientQuota = errors.New("framequery: insufficient quota")
+
+	// ErrPartialResult is returned (wrapped), with a result, when a wait
+	// with ProcessOptions.ReturnPartialOnTimeout times out on a job that has
+	// some processed data.
+	ErrPartialResult = errors.New("framequery: partial result")
+
+	// ErrSourceTooLong matches a *SourceTooLongError.
+	ErrSourceTooLong = errors.New("framequery: source too long")
+
+	// ErrSubscriptionStopped is returned by Subscription.Err after Stop.
+	ErrSubscriptionStopped = errors.New("framequery: subscription stopped")
+
+	// ErrNoAPIKey is returned by DefaultClient and the package-level
+	// functions when FRAMEQUERY_API_KEY is unset.
+	ErrNoAPIKey = errors.New("framequery: no API key (set FRAMEQUERY_API_KEY or call SetDefaultClient)")
+
+	// ErrInvalidAPIKey is returned (wrapped) by ValidateAPIKey when the API
+	// rejects the key.
+	ErrInvalidAPIKey = errors.New("framequery: invalid API key")
+
+	// ErrAPIKeyExpired is returned (wrapped) by ValidateAPIKey for an expired
+	// key. It also matches ErrInvalidAPIKey.
+	ErrAPIKeyExpired = fmt.Errorf("%w: expired", ErrInvalidAPIKey)
+)
+
+// JobNotCompleteError is returned by GetResult for a job that is still processing.
+// ETASeconds is the API's estimate of the time remaining, 0 if unknown.
+type JobNotCompleteError struct {
+	JobID      string
+	Status     JobStatus
+	ETASeconds float64
+}
+
+func (e *JobNotCompleteError) Error() string {
+	return fmt.Sprintf("framequery: job %s not complete (status %s)", e.JobID, e.Status)
+}
+
+// Is matches ErrJobNotComplete.
+func (e *JobNotCompleteError) Is(target error) bool {
+	return target == ErrJobNotComplete
+}
+
+// ResultExpiredError is returned for a job whose results were deleted under
+// the retention policy.
+type ResultExpiredError struct {
+	JobID string
+}
+
+func (e *ResultExpiredError) Error() string {
+	return fmt.Sprintf("framequery: job %s result expired", e.JobID)
+}
+
+// Is matches ErrResultExpired.
+func (e *ResultExpiredError) Is(target error) bool {
+	return target == ErrResultExpired
+}
+
+// ResultNotReadyError is returned by GetResult for a completed job whose
+// processed data is not available yet.
+type ResultNotReadyError struct {
+	JobID  string
+	Status JobStatus
+}
+
+func (e *ResultNotReadyError) Error() string {
+	return fmt.Sprintf("framequery: job %s result not ready (status %s)", e.JobID, e.Status)
+}
+
+// Is matches ErrResultNotReady.
+func (e *ResultNotReadyError) Is(target error) bool {
+	return target == ErrResultNotReady
+}
+
+// SourceTooLongError is returned, before any job is created, when
+// UploadOptions.SourceDuration exceeds MaxSourceDuration.
+type SourceTooLongError struct {
+	Duration time.Duration
+	Max      time.Duration
+}
+
+func (e *SourceTooLongError) Error() string {
+	return fmt.Sprintf("framequery: source is %s long, over the %s limit", e.Duration, e.Max)
+}
+
+// Is matches ErrSourceTooLong.
+func (e *SourceTooLongError) Is(target error) bool {
+	return target == ErrSourceTooLong
+}
+
+// JobFailedError is returned when a job ends in a FAILED status. Message and
+// Code are the API's errorMessage and errorCode, and may be empty.
+type JobFailedError struct {
+	JobID   string
+	Status  JobStatus
+	Message string
+	Code    string
+}
+
+// UserCorrectable reports whether the failure was caused by the input rather
+// than the service: a URL that couldn't be fetched or a file that couldn't be
+// transcoded. Retrying those unchanged will fail again; other failures may
+// succeed on retry.
+func (e *JobFailedError) UserCorrectable() bool {
+	return e.Status == StatusFailedFetch || e.Status == StatusIngestFailedTranscode
+}
+
+func (e *JobFailedError) Error() string {
+	msg := fmt.Sprintf("framequery: job %s failed (status %s)", e.JobID, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Code != "" {
+		msg += " [" + e.Code + "]"
+	}
+	return msg
+}
+
+// Is matches ErrJobFailed.
+func (e *JobFailedError) Is(target error) bool {
+	return target == ErrJobFailed
+}
+
+// Error is an API error. StatusCode is 0 for non-HTTP errors, such as a
+// request that never got a response or one whose body couldn't be read or
+// decoded; Err then holds the cause. RequestID comes from the X-Request-Id
+// response header; include it when contacting support. RetryAfter is parsed
+// from the Retry-After header.
 type Error struct {
 	Message    string
 	StatusCode int
 	Body       map[string]any
+	RequestID  string
+	RetryAfter time.Duration
+	Path       string // API path requested, without the query
+	Err        error  // underlying cause, if any
 }
 
 func (e *Error) Error() string {
+	msg := fmt.Sprintf("framequery: %s", e.Message)
 	if e.StatusCode > 0 {
-		return fmt.Sprintf("framequery: API error %d: %s", e.StatusCode, e.Message)
+		msg = fmt.Sprintf("framequery: API error %d: %s", e.StatusCode, e.Message)
+	}
+	if e.RequestID != "" {
+		msg += " (request " + e.RequestID + ")"
+	}
+	return msg
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// collectionPaths are the /jobs/ endpoints that aren't a job.
+var collectionPaths = map[string]bool{"batch": true, "counts": true, "from-url": true}
+
+// isJobPath reports whether path addresses a job, /jobs/{id} or below. An
+// empty path, as on an Error built by hand, counts as one.
+func isJobPath(path string) bool {
+	if path == "" {
+		return true
+	}
+	id, ok := strings.CutPrefix(path, "/jobs/")
+	if !ok {
+		return false
+	}
+	id, _, _ = strings.Cut(id, "/")
+	return id != "" && !collectionPaths[id]
+}
+
+// Is matches the sentinel error for e's status code.
+func (e *Error) Is(target error) bool {
+	switch e.StatusCode {
+	case 401:
+		return target == ErrUnauthorized
+	case 403:
+		return target == ErrForbidden
+	case 404:
+		return target == ErrNotFound || (target == ErrJobNotFound && isJobPath(e.Path))
+	case 409:
+		return target == ErrConflict
+	case 410:
+		return target == ErrSourcePurged
+	case 429:
+		return target == ErrRateLimited
 	}
-	return fmt.Sprintf("framequery: %s", e.Message)
+	return false
+}
+
+// expiredKey reports whether a 401 says the key expired, via an error code
+// or message mentioning expiry.
+func (e *Error) expiredKey() bool {
+	code, _ := e.Body["code"].(string)
+	return strings.Contains(strings.ToLower(code), "expired") ||
+		strings.Contains(strings.ToLower(e.Message), "expired")
+}
+
+// CallbackPanicError is returned when a user callback (e.g. OnProgress)
+// panics. The wait is aborted; the job keeps running server-side.
+type CallbackPanicError struct {
+	Callback string
+	Value    any
+	Stack    []byte
+}
+
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("framequery: %s callback panicked: %v", e.Callback, e.Value)
+}
+
+// GetJobsError reports per-job failures from GetJobs. Errors lines up with
+// the requested IDs; nil entries succeeded.
+type GetJobsError struct {
+	Errors []error
+}
+
+func (e *GetJobsError) Error() string {
+	n := 0
+	var first error
+	for _, err := range e.Errors {
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			n++
+		}
+	}
+	return fmt.Sprintf("framequery: %d of %d jobs failed to load (first: %v)", n, len(e.Errors), first)
+}
+
+// DecodeError describes a list item that couldn't be decoded as a job. Index
+// is its position in the response page and Item its raw decoded value.
+type DecodeError struct {
+	Index int
+	Item  any
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("framequery: decode list item %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying decode error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// hasStatus reports whether err's chain holds an *Error with the given status code.
+func hasStatus(err error, code int) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == code
 }
 
 // IsAuthError checks for 401 Unauthorized.
 func IsAuthError(err error) bool {
-	e, ok := err.(*Error)
-	return ok && e.StatusCode == 401
+	return hasStatus(err, 401)
 }
 
 // IsNotFoundError checks for 404 Not Found.
 func IsNotFoundError(err error) bool {
-	e, ok := err.(*Error)
-	return ok && e.StatusCode == 404
+	return hasStatus(err, 404)
 }
 
 // IsRateLimitError checks for 429 Too Many Requests.
 func IsRateLimitError(err error) bool {
-	e, ok := err.(*Error)
-	return ok && e.StatusCode == 429
+	return hasStatus(err, 429)
 }
 
 // IsPermissionError checks for 403 Forbidden.
 func IsPermissionError(err error) bool {
-	e, ok := err.(*Error)
-	return ok && e.StatusCode == 403
+	return hasStatus(err, 403)
+}
+
+// IsConflictError checks for 409 Conflict (e.g. cancelling a finished job).
+func IsConflictError(err error) bool {
+	return hasStatus(err, 409)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	ra := h.Get("Retry-After")
+	if ra == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseFloat(ra, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if t, err := http.ParseTime(ra); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
 }
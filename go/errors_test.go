@@ -0,0 +1,188 @@
+package framequery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		msg, code   string
+		correctable bool
+		text        string
+	}{
+		{
+			name:        "with fields",
+			body:        `{"jobId":"j1","status":"INGEST_FAILED_TRANSCODE","errorMessage":"unsupported codec","errorCode":"UNSUPPORTED_CODEC"}`,
+			msg:         "unsupported codec",
+			code:        "UNSUPPORTED_CODEC",
+			correctable: true,
+			text:        "framequery: job j1 failed (status INGEST_FAILED_TRANSCODE): unsupported codec [UNSUPPORTED_CODEC]",
+		},
+		{
+			name: "without fields",
+			body: `{"jobId":"j2","status":"FAILED"}`,
+			text: "framequery: job j2 failed (status FAILED)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := decodeJob([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.ErrorMessage != tt.msg || job.ErrorCode != tt.code {
+				t.Errorf("job error fields = %q, %q; want %q, %q", job.ErrorMessage, job.ErrorCode, tt.msg, tt.code)
+			}
+			err = jobFailure(job)
+			var fe *JobFailedError
+			if !errors.As(err, &fe) || !errors.Is(err, ErrJobFailed) {
+				t.Fatalf("jobFailure = %v, want a *JobFailedError", err)
+			}
+			if fe.Message != tt.msg || fe.Code != tt.code {
+				t.Errorf("error fields = %q, %q; want %q, %q", fe.Message, fe.Code, tt.msg, tt.code)
+			}
+			if fe.UserCorrectable() != tt.correctable {
+				t.Errorf("UserCorrectable = %v, want %v", fe.UserCorrectable(), tt.correctable)
+			}
+			if err.Error() != tt.text {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.text)
+			}
+		})
+	}
+}
+
+func TestResultErrorEveryStatus(t *testing.T) {
+	statuses := []JobStatus{"NEW_STAGE", "SOMETHING_FAILED"} // undocumented
+	for st := range jobStates {
+		statuses = append(statuses, st)
+	}
+	for _, st := range statuses {
+		for _, withData := range []bool{false, true} {
+			body := fmt.Sprintf(`{"jobId":"j1","status":%q,"estimatedCompletionTimeSeconds":42}`, st)
+			if withData {
+				body = fmt.Sprintf(`{"jobId":"j1","status":%q,"processedData":{"length":3}}`, st)
+			}
+			job, err := decodeJob([]byte(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = resultError(job)
+
+			var nc *JobNotCompleteError
+			var fe *JobFailedError
+			var re *ResultExpiredError
+			var nr *ResultNotReadyError
+			switch state := stateOf(st); state {
+			case stateRunning:
+				if !errors.As(err, &nc) || !errors.Is(err, ErrJobNotComplete) || nc.Status != st {
+					t.Errorf("%s: err = %v, want a *JobNotCompleteError", st, err)
+				} else if !withData && nc.ETASeconds != 42 {
+					t.Errorf("%s: ETASeconds = %v, want 42", st, nc.ETASeconds)
+				}
+			case stateComplete:
+				switch {
+				case withData && err != nil:
+					t.Errorf("%s with data: err = %v, want nil", st, err)
+				case !withData && (!errors.As(err, &nr) || !errors.Is(err, ErrResultNotReady) || nr.JobID != "j1" || nr.Status != st):
+					t.Errorf("%s without data: err = %v, want a *ResultNotReadyError", st, err)
+				}
+			case stateFailed:
+				if !errors.As(err, &fe) || fe.Status != st {
+					t.Errorf("%s: err = %v, want a *JobFailedError", st, err)
+				}
+			case stateCancelled:
+				if !errors.Is(err, ErrJobCancelled) {
+					t.Errorf("%s: err = %v, want ErrJobCancelled", st, err)
+				}
+			case stateExpired:
+				if !errors.As(err, &re) || !errors.Is(err, ErrResultExpired) || re.JobID != "j1" {
+					t.Errorf("%s: err = %v, want a *ResultExpiredError", st, err)
+				}
+			default:
+				t.Fatalf("%s: state %d has no expected error", st, state)
+			}
+			if err != nil && !strings.HasPrefix(err.Error(), "framequery: job j1") {
+				t.Errorf("%s: message %q doesn't name the job", st, err.Error())
+			}
+		}
+	}
+}
+
+func TestNotFoundSentinels(t *testing.T) {
+	tests := []struct {
+		path string
+		job  bool
+	}{
+		{"", true},
+		{"/jobs/j1", true},
+		{"/jobs/j1/retry", true},
+		{"/jobs/j1/result", true},
+		{"/jobs", false},
+		{"/jobs/", false},
+		{"/jobs/counts", false},
+		{"/jobs/from-url", false},
+		{"/jobs/batch", false},
+		{"/quota", false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", &Error{StatusCode: 404, Path: tt.path})
+		if !errors.Is(err, ErrNotFound) || !IsNotFoundError(err) {
+			t.Errorf("%q: not ErrNotFound", tt.path)
+		}
+		if got := errors.Is(err, ErrJobNotFound); got != tt.job {
+			t.Errorf("%q: errors.Is(err, ErrJobNotFound) = %v, want %v", tt.path, got, tt.job)
+		}
+	}
+
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	if _, err := c.GetJob(context.Background(), "j1"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("GetJob: err = %v, want ErrJobNotFound", err)
+	}
+	if _, err := c.GetQuota(context.Background()); !errors.Is(err, ErrNotFound) || errors.Is(err, ErrJobNotFound) {
+		t.Errorf("GetQuota: err = %v, want ErrNotFound only", err)
+	}
+}
+
+func TestErrorCause(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jobs/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{"data":`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetJob(ctx, "slow")
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 0 || apiErr.Path != "/jobs/slow" || apiErr.Err == nil {
+		t.Fatalf("err = %#v, want an *Error carrying the transport failure", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want it to match context.DeadlineExceeded", err)
+	}
+
+	_, err = c.GetJob(context.Background(), "j1")
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &apiErr) || !errors.As(err, &syntaxErr) {
+		t.Errorf("err = %v, want an *Error wrapping the *json.SyntaxError", err)
+	}
+	if IsNotFoundError(err) || errors.Is(err, ErrJobNotFound) {
+		t.Errorf("decode failure %v matches a status sentinel", err)
+	}
+}
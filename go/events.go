@@ -0,0 +1,77 @@
+package framequery
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JobEvent is one status transition in a job's history.
+type JobEvent struct {
+	Status    JobStatus `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// isPending reports whether s is one of the PENDING_ states a job is in
+// before it is queued, such as PENDING_UPLOAD or PENDING_FETCH.
+func isPending(s JobStatus) bool {
+	return strings.HasPrefix(string(s), "PENDING")
+}
+
+// JobEvents is a job's status history, oldest first.
+type JobEvents []JobEvent
+
+// GetJobEvents returns a job's status transitions sorted by timestamp.
+func (c *Client) GetJobEvents(ctx context.Context, jobID string) (JobEvents, error) {
+	var events JobEvents
+	if err := c.doJSON(ctx, http.MethodGet, "/jobs/"+url.PathEscape(jobID)+"/events", nil, &events); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// QueueDuration is the time from entering QUEUED to the next transition.
+// Returns 0 if the job was never queued or is still queued.
+func (e JobEvents) QueueDuration() time.Duration {
+	for i, ev := range e {
+		if ev.Status == StatusQueued {
+			for _, next := range e[i+1:] {
+				if next.Status != StatusQueued {
+					return next.Timestamp.Sub(ev.Timestamp)
+				}
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// ProcessingDuration is the time from the first status past the queue, after
+// any PENDING_ state and QUEUED, to the first terminal status. Returns 0 while
+// the job is still running.
+func (e JobEvents) ProcessingDuration() time.Duration {
+	start := -1
+	for i, ev := range e {
+		if !isPending(ev.Status) && ev.Status != StatusQueued {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return 0
+	}
+	for _, ev := range e[start:] {
+		j := Job{Status: ev.Status}
+		if j.IsTerminal() {
+			return ev.Timestamp.Sub(e[start].Timestamp)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package framequery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetJobEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/jobs/j1/events" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL)
+		}
+		// Out of order, as the API may send them
+		w.Write([]byte(`{"data":[
+			{"status":"QUEUED","timestamp":"2026-03-01T10:00:05Z"},
+			{"status":"VISION_COMPLETED","timestamp":"2026-03-01T10:03:05Z","message":"done"},
+			{"status":"PENDING_FETCH","timestamp":"2026-03-01T10:00:00Z"},
+			{"status":"INGEST_PROCESSING","timestamp":"2026-03-01T10:01:05Z"}
+		]}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+
+	events, err := c.GetJobEvents(context.Background(), "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []JobStatus
+	for _, ev := range events {
+		got = append(got, ev.Status)
+	}
+	want := []JobStatus{StatusPendingFetch, StatusQueued, StatusIngestProcessing, StatusVisionCompleted}
+	if !slices.Equal(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if events[3].Message != "done" {
+		t.Errorf("message = %q, want done", events[3].Message)
+	}
+	if d := events.QueueDuration(); d != time.Minute {
+		t.Errorf("QueueDuration = %v, want 1m", d)
+	}
+	if d := events.ProcessingDuration(); d != 2*time.Minute {
+		t.Errorf("ProcessingDuration = %v, want 2m, not counting the fetch or the queue", d)
+	}
+}
+
+func TestEventDurations(t *testing.T) {
+	at := func(sec int) time.Time { return time.Date(2026, 3, 1, 10, 0, sec, 0, time.UTC) }
+	tests := []struct {
+		name              string
+		events            JobEvents
+		queued, processed time.Duration
+	}{
+		{"upload", JobEvents{{StatusPendingUpload, at(0), ""}, {StatusQueued, at(10), ""}, {StatusIngestProcessing, at(15), ""}, {StatusVisionCompleted, at(45), ""}}, 5 * time.Second, 30 * time.Second},
+		{"never queued", JobEvents{{StatusIngestProcessing, at(0), ""}, {StatusFailed, at(20), ""}}, 0, 20 * time.Second},
+		{"still queued", JobEvents{{StatusPendingFetch, at(0), ""}, {StatusQueued, at(3), ""}}, 0, 0},
+		{"still running", JobEvents{{StatusQueued, at(0), ""}, {StatusVisionProcessing, at(3), ""}}, 3 * time.Second, 0},
+		{"empty", nil, 0, 0},
+	}
+	for _, tt := range tests {
+		if d := tt.events.QueueDuration(); d != tt.queued {
+			t.Errorf("%s: QueueDuration = %v, want %v", tt.name, d, tt.queued)
+		}
+		if d := tt.events.ProcessingDuration(); d != tt.processed {
+			t.Errorf("%s: ProcessingDuration = %v, want %v", tt.name, d, tt.processed)
+		}
+	}
+}
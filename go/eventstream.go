@@ -0,0 +1,91 @@
+package framequery
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// EventSchemaVersion is the value of ProgressEvent.Version. It changes only
+// when a field is removed or changes meaning; new fields may be added freely.
+const EventSchemaVersion = 1
+
+// Progress event types written to ProcessOptions.EventWriter.
+const (
+	EventJobCreated     = "job_created"     // jobId
+	EventUploadProgress = "upload_progress" // jobId, bytesSent, bytesTotal
+	EventStatusChange   = "status_change"   // jobId, status, prevStatus
+	EventPoll           = "poll"            // jobId, status, etaSeconds
+	EventPartialResult  = "partial_result"  // jobId, status; processed data is available before completion
+	EventTerminal       = "terminal"        // jobId, status, error (if failed)
+)
+
+// ProgressEvent is one line of the NDJSON stream written to an EventWriter.
+type ProgressEvent struct {
+	Version    int       `json:"v"`
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	JobID      string    `json:"jobId,omitempty"`
+	Status     JobStatus `json:"status,omitempty"`
+	PrevStatus JobStatus `json:"prevStatus,omitempty"`
+	ETASeconds float64   `json:"etaSeconds,omitempty"`
+	BytesSent  int64     `json:"bytesSent,omitempty"`
+	BytesTotal int64     `json:"bytesTotal,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// eventWriter serializes ProgressEvents to w, one JSON object per line and one
+// Write call per event. Nothing else is ever written to w. A nil *eventWriter
+// discards events.
+type eventWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newEventWriter(w io.Writer) *eventWriter {
+	if w == nil {
+		return nil
+	}
+	return &eventWriter{w: w}
+}
+
+func (e *eventWriter) emit(ev ProgressEvent) {
+	if e == nil {
+		return
+	}
+	ev.Version = EventSchemaVersion
+	ev.Time = time.Now().UTC()
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	b = append(b, '\n')
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.w.Write(b)
+}
+
+// progressReader emits upload_progress events as r is read, at most once per
+// whole percent.
+type progressReader struct {
+	r       io.Reader
+	events  *eventWriter
+	jobID   string
+	total   int64
+	sent    int64
+	lastPct int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+	if p.total > 0 {
+		pct := p.sent * 100 / p.total
+		if pct > p.lastPct || err == io.EOF {
+			p.lastPct = pct
+			p.events.emit(ProgressEvent{Type: EventUploadProgress, JobID: p.jobID, BytesSent: p.sent, BytesTotal: p.total})
+		}
+	}
+	return n, err
+}
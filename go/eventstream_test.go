@@ -0,0 +1,111 @@
+package framequery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeRecorder keeps each Write call separately.
+type writeRecorder struct {
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, bytes.Clone(p))
+	return len(p), nil
+}
+
+// events decodes the recorded writes, failing unless each is one JSON line.
+func (w *writeRecorder) events(t *testing.T) []ProgressEvent {
+	t.Helper()
+	var out []ProgressEvent
+	for _, b := range w.writes {
+		if bytes.Count(b, []byte("\n")) != 1 || b[len(b)-1] != '\n' {
+			t.Fatalf("write %q is not exactly one line", b)
+		}
+		var ev ProgressEvent
+		if err := json.Unmarshal(b, &ev); err != nil {
+			t.Fatalf("write %q: %v", b, err)
+		}
+		if ev.Version != EventSchemaVersion || ev.Time.IsZero() {
+			t.Errorf("event %q lacks a version or time", b)
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+func TestEventWriterProcessURL(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"PENDING_FETCH"}}`))
+			return
+		}
+		if polls++; polls == 1 {
+			w.Write([]byte(`{"data":{"jobId":"j1","status":"VIDEO_PROCESSING","estimatedCompletionTimeSeconds":30}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":2}}}`))
+	}))
+	defer srv.Close()
+	var rec writeRecorder
+
+	_, err := New("k", WithBaseURL(srv.URL)).ProcessURL(context.Background(), "https://cdn.example.com/v.mp4",
+		&ProcessOptions{PollInterval: time.Millisecond, EventWriter: &rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ProgressEvent{
+		{Type: EventJobCreated, JobID: "j1"},
+		{Type: EventPoll, JobID: "j1", Status: StatusVideoProcessing, ETASeconds: 30},
+		{Type: EventStatusChange, JobID: "j1", Status: StatusVideoProcessing},
+		{Type: EventPoll, JobID: "j1", Status: StatusVisionCompleted},
+		{Type: EventStatusChange, JobID: "j1", Status: StatusVisionCompleted, PrevStatus: StatusVideoProcessing},
+		{Type: EventTerminal, JobID: "j1", Status: StatusVisionCompleted},
+	}
+	got := rec.events(t)
+	if len(got) != len(want) {
+		t.Fatalf("%d events, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range got {
+		got[i].Version, got[i].Time = 0, time.Time{}
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEventWriterUploadProgress(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	var rec writeRecorder
+
+	_, err := New("k", WithBaseURL(srv.URL)).Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{EventWriter: &rec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := rec.events(t)
+	if len(got) < 2 || got[0].Type != EventJobCreated {
+		t.Fatalf("events = %+v, want job_created then upload progress", got)
+	}
+	var sent int64
+	for _, ev := range got[1:] {
+		if ev.Type != EventUploadProgress || ev.BytesTotal != int64(len(uploadContent)) || ev.BytesSent < sent {
+			t.Errorf("event %+v, want upload_progress counting up to %d", ev, len(uploadContent))
+		}
+		sent = ev.BytesSent
+	}
+	if sent != int64(len(uploadContent)) {
+		t.Errorf("last upload_progress sent %d bytes, want %d", sent, len(uploadContent))
+	}
+}
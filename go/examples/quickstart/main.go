@@ -63,7 +63,7 @@ func main() {
 	fmt.Printf("Plan: %s, Credits: %.1fh remaining\n", quota.Plan, quota.CreditsBalanceHours)
 
 	// 5. List jobs
-	page, err := client.ListJobs(ctx, &framequery.ListJobsOptions{Limit: 10, Status: "COMPLETED"})
+	page, err := client.ListJobs(ctx, &framequery.ListJobsOptions{Limit: 10, Status: framequery.StatusVisionCompleted})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,115 @@
+package framequery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// FingerprintVersion identifies the canonicalization used by Fingerprint.
+// Fingerprints are comparable only when their version prefix matches; it
+// changes whenever the rules below do.
+const FingerprintVersion = 1
+
+// Canonical form, version 1:
+//   - only analysis output is hashed: Duration, DetectedLanguage, Features,
+//     VideoSummary, Keywords, Topics, Scenes, and Transcript. Raw, RawBody, Manifest, Status, and identifying
+//     or per-fetch fields (JobID, Filename, CreatedAt, KeyFrameURL, which is a
+//     signed, expiring URL) are excluded
+//   - times are whole milliseconds via secondsToMillis
+//   - confidences are formatted with 4 decimal places
+//   - speakers, summaries, keywords, and topics are included only when set,
+//     so results without them hash as before; keyword and topic order is kept
+//   - features are sorted; scene, object, and transcript order is kept
+//   - the result is encoded as JSON from fixed-order structs
+type canonResult struct {
+	DurationMs int64          `json:"d"`
+	Language   string         `json:"l"`
+	Features   []string       `json:"f"`
+	Scenes     []canonScene   `json:"s"`
+	Transcript []canonSegment `json:"t"`
+	Summary    string         `json:"m,omitempty"`
+	Keywords   []string       `json:"w,omitempty"`
+	Topics     []string       `json:"p,omitempty"`
+}
+
+type canonScene struct {
+	Description string        `json:"d"`
+	EndMs       int64         `json:"e"`
+	Objects     []canonObject `json:"o"`
+	Tracks      []canonTrack  `json:"k"`
+	Summary     string        `json:"m,omitempty"`
+	Keywords    []string      `json:"w,omitempty"`
+}
+
+type canonObject struct {
+	Label      string `json:"l"`
+	Confidence string `json:"c"`
+}
+
+type canonTrack struct {
+	Label      string     `json:"l"`
+	Confidence string     `json:"c"`
+	Spans      [][2]int64 `json:"s"`
+}
+
+type canonSegment struct {
+	StartMs int64  `json:"s"`
+	EndMs   int64  `json:"e"`
+	Text    string `json:"t"`
+	Speaker string `json:"p,omitempty"`
+}
+
+// Fingerprint returns a stable hash of the result's content, formatted as
+// "v<FingerprintVersion>:<hex sha256>". It changes when the analysis output
+// changes and not otherwise, so it can detect results regenerated
+// server-side.
+func (r *ProcessingResult) Fingerprint() string {
+	c := canonResult{
+		DurationMs: secondsToMillis(r.Duration),
+		Language:   r.DetectedLanguage,
+		Features:   []string{},
+		Scenes:     []canonScene{},
+		Transcript: []canonSegment{},
+		Summary:    r.VideoSummary,
+		Keywords:   r.Keywords,
+		Topics:     r.Topics,
+	}
+	for _, f := range r.Features {
+		c.Features = append(c.Features, string(f))
+	}
+	sort.Strings(c.Features)
+	for _, s := range r.Scenes {
+		cs := canonScene{Description: s.Description, EndMs: s.EndMillis(), Objects: []canonObject{}, Tracks: []canonTrack{}, Summary: s.Summary, Keywords: s.Keywords}
+		if len(s.Detections) > 0 {
+			for _, d := range s.Detections {
+				cs.Objects = append(cs.Objects, canonObject{Label: d.Label, Confidence: canonFloat(d.Confidence)})
+			}
+		} else {
+			for _, l := range s.Objects {
+				cs.Objects = append(cs.Objects, canonObject{Label: l})
+			}
+		}
+		for _, t := range s.ObjectTracks {
+			ct := canonTrack{Label: t.Label, Confidence: canonFloat(t.Confidence), Spans: [][2]int64{}}
+			for _, sp := range t.Spans {
+				ct.Spans = append(ct.Spans, [2]int64{secondsToMillis(sp.Start), secondsToMillis(sp.End)})
+			}
+			cs.Tracks = append(cs.Tracks, ct)
+		}
+		c.Scenes = append(c.Scenes, cs)
+	}
+	for _, t := range r.Transcript {
+		c.Transcript = append(c.Transcript, canonSegment{StartMs: t.StartMillis(), EndMs: t.EndMillis(), Text: t.Text, Speaker: t.Speaker})
+	}
+
+	b, _ := json.Marshal(c)
+	sum := sha256.Sum256(b)
+	return "v" + strconv.Itoa(FingerprintVersion) + ":" + hex.EncodeToString(sum[:])
+}
+
+func canonFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
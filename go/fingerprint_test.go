@@ -0,0 +1,68 @@
+package framequery
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// fingerprintOf decodes a job body and fingerprints its result.
+func fingerprintOf(t *testing.T, body []byte) string {
+	t.Helper()
+	job, err := decodeJob(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := job.Result()
+	if !ok {
+		t.Fatal("job has no result")
+	}
+	return r.Fingerprint()
+}
+
+// fullJobFingerprint pins fullJobFixture's version 1 fingerprint. If it
+// changes, the canon changed and FingerprintVersion must too.
+const fullJobFingerprint = "v1:335eae2d522bf37186fc4d5d93a729f1c11b7ad107b8974cad4c03f090de6bb9"
+
+func TestFingerprintStable(t *testing.T) {
+	base := fingerprintOf(t, []byte(fullJobFixture))
+	if base != fullJobFingerprint {
+		t.Fatalf("Fingerprint = %q, want %q", base, fullJobFingerprint)
+	}
+
+	// Re-encoding sorts every object's keys, so the same content arrives in
+	// a different order; the additions are fields the canon form ignores.
+	var m map[string]any
+	if err := json.Unmarshal([]byte(fullJobFixture), &m); err != nil {
+		t.Fatal(err)
+	}
+	m["createdAt"] = "2027-01-01T00:00:00Z"
+	m["newTopLevel"] = map[string]any{"z": 1, "a": []any{"x"}}
+	m["features"] = []any{"transcript", "scenes"}
+	pd := m["processedData"].(map[string]any)
+	pd["modelVersion"] = "2026.10"
+	scene := pd["scenes"].([]any)[0].(map[string]any)
+	scene["keyFrameUrl"] = "https://x/1.jpg?X-Amz-Signature=other"
+	scene["embedding"] = []any{0.1, 0.2}
+	shuffled, _ := json.Marshal(m)
+	if got := fingerprintOf(t, shuffled); got != base {
+		t.Errorf("fingerprint changed with key order and ignored fields:\n%s\n%s", got, base)
+	}
+
+	changed := strings.Replace(fullJobFixture, `"description": "slides"`, `"description": "slide deck"`, 1)
+	if got := fingerprintOf(t, []byte(changed)); got == base {
+		t.Error("fingerprint unchanged after a scene description changed")
+	}
+	jitter := strings.Replace(fullJobFixture, `"EndTime": 4.5`, `"EndTime": 4.5000001`, 1)
+	if got := fingerprintOf(t, []byte(jitter)); got != base {
+		t.Error("fingerprint changed with sub-millisecond float noise")
+	}
+}
+
+func TestFingerprintEmpty(t *testing.T) {
+	var nilSlices, emptySlices ProcessingResult
+	emptySlices.Scenes, emptySlices.Transcript, emptySlices.Features = []Scene{}, []TranscriptSegment{}, []Feature{}
+	if nilSlices.Fingerprint() != emptySlices.Fingerprint() {
+		t.Error("nil and empty slices fingerprint differently")
+	}
+}
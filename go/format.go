@@ -0,0 +1,54 @@
+package framequery
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maxSummaryField bounds user-supplied strings such as filenames in String
+// output.
+const maxSummaryField = 64
+
+// String returns Summary, so %v never dumps Raw.
+func (j Job) String() string { return j.Summary() }
+
+// Summary is a one-line, log-safe description of the job: ID, status,
+// filename, and ETA. It never includes Raw, metadata, or tags.
+func (j Job) Summary() string {
+	s := fmt.Sprintf("Job{id=%s status=%s", j.ID, j.Status)
+	if j.Filename != "" {
+		s += " file=" + strconv.Quote(truncate(j.Filename, maxSummaryField))
+	}
+	if j.ETASeconds > 0 {
+		s += fmt.Sprintf(" eta=%.0fs", j.ETASeconds)
+	}
+	return s + "}"
+}
+
+// String returns Summary, so %v never dumps the transcript or Raw.
+func (r ProcessingResult) String() string { return r.Summary() }
+
+// Summary is a one-line, log-safe description of the result: job ID, status,
+// duration, and scene and segment counts. It never includes scene or
+// transcript text, or Raw.
+func (r ProcessingResult) Summary() string {
+	s := fmt.Sprintf("ProcessingResult{job=%s status=%s duration=%.1fs scenes=%d segments=%d",
+		r.JobID, r.Status, r.Duration, len(r.Scenes), len(r.Transcript))
+	if r.Err != nil {
+		s += " err=" + strconv.Quote(truncate(r.Err.Error(), maxSummaryField))
+	}
+	return s + "}"
+}
+
+// String describes the plan and remaining hours.
+func (q Quota) String() string {
+	return fmt.Sprintf("Quota{plan=%s available=%.1fh used=%.1fh}", q.Plan, q.TotalAvailableHours(), q.UsedHours)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
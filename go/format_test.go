@@ -0,0 +1,53 @@
+package framequery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// maxSummaryLen is the most any Summary should print, however large the value.
+const maxSummaryLen = 200
+
+func TestSummariesBoundedAndLogSafe(t *testing.T) {
+	const secret = "transcript-secret-text"
+	long := strings.Repeat("very long name ", 100)
+
+	job, err := decodeJobRaw([]byte(fmt.Sprintf(`{
+		"jobId": "job_1", "status": "VISION_COMPLETED", "originalFilename": %q,
+		"estimatedCompletionTimeSeconds": 90, "metadata": {"apiKey": %q}, "tags": [%q],
+		"processedData": {"length": 60, "transcript": [{"StartTime": 0, "EndTime": 2, "Text": %q}]}
+	}`, long, secret, secret, strings.Repeat(secret+" ", 1000))), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := job.result()
+	result.Err = errors.New(strings.Repeat("boom ", 100))
+
+	for name, s := range map[string]string{
+		"Job %v":               fmt.Sprintf("%v", *job),
+		"*Job %+v":             fmt.Sprintf("%+v", job),
+		"Job.Summary":          job.Summary(),
+		"ProcessingResult %v":  fmt.Sprintf("%v", *result),
+		"*ProcessingResult %v": fmt.Sprintf("%v", result),
+		"Quota":                Quota{Plan: "pro", UsedHours: 1}.String(),
+	} {
+		if len(s) > maxSummaryLen {
+			t.Errorf("%s is %d bytes, want at most %d: %s", name, len(s), maxSummaryLen, s)
+		}
+		if strings.Contains(s, secret) {
+			t.Errorf("%s leaks transcript, metadata, or tags: %s", name, s)
+		}
+		if strings.Contains(s, "\n") {
+			t.Errorf("%s spans several lines", name)
+		}
+	}
+
+	if s := job.Summary(); !strings.Contains(s, "job_1") || !strings.Contains(s, "VISION_COMPLETED") || !strings.Contains(s, "eta=90s") {
+		t.Errorf("Job.Summary() = %s, want the ID, status, and ETA", s)
+	}
+	if s := result.Summary(); !strings.Contains(s, "scenes=0 segments=1") || !strings.Contains(s, "duration=60.0s") {
+		t.Errorf("ProcessingResult.Summary() = %s, want the duration and counts", s)
+	}
+}
@@ -0,0 +1,138 @@
+package framequery
+
+import (
+	"sort"
+	"time"
+)
+
+// ResultIndex answers time queries on a result in O(log n). It is immutable
+// once built, so any number of goroutines may query it at once. Scenes are
+// ordered by EndTime and span from their StartTime, as in ScenesInRange;
+// transcript segments may be in any order and may overlap.
+type ResultIndex struct {
+	scenes    []Scene
+	sceneEnds []float64
+
+	segs   []TranscriptSegment // sorted by StartTime
+	starts []float64
+	maxEnd []float64 // maxEnd[i] is the latest EndTime among segs[:i+1]
+
+	// First elements of the slices the index was built from, to detect
+	// replacement
+	scenePtr *Scene
+	segPtr   *TranscriptSegment
+}
+
+// Index returns an index over r's scenes and transcript, building it on first
+// use and caching it on r. The cached index is rebuilt when Scenes or
+// Transcript has since been replaced or resized; edits to individual elements
+// in place are not detected.
+//
+// Index may be called concurrently, but not while r is being modified.
+func (r *ProcessingResult) Index() *ResultIndex {
+	if idx, _ := r.index.Load().(*ResultIndex); idx != nil && idx.matches(r) {
+		return idx
+	}
+	// Concurrent first calls may each build one; they are identical
+	idx := newResultIndex(r.Scenes, r.Transcript)
+	r.index.Store(idx)
+	return idx
+}
+
+func newResultIndex(scenes []Scene, transcript []TranscriptSegment) *ResultIndex {
+	idx := &ResultIndex{
+		scenes:    sceneSpans(scenes),
+		sceneEnds: make([]float64, len(scenes)),
+		segs:      append([]TranscriptSegment(nil), transcript...),
+		starts:    make([]float64, len(transcript)),
+		maxEnd:    make([]float64, len(transcript)),
+	}
+	if len(scenes) > 0 {
+		idx.scenePtr = &scenes[0]
+	}
+	if len(transcript) > 0 {
+		idx.segPtr = &transcript[0]
+	}
+	for i, s := range idx.scenes {
+		idx.sceneEnds[i] = s.EndTime
+	}
+	sort.SliceStable(idx.segs, func(a, b int) bool { return idx.segs[a].StartTime < idx.segs[b].StartTime })
+	for i, t := range idx.segs {
+		idx.starts[i] = t.StartTime
+		idx.maxEnd[i] = t.EndTime
+		if i > 0 && idx.maxEnd[i-1] > t.EndTime {
+			idx.maxEnd[i] = idx.maxEnd[i-1]
+		}
+	}
+	return idx
+}
+
+// matches reports whether idx was built from r's current slices.
+func (idx *ResultIndex) matches(r *ProcessingResult) bool {
+	if len(r.Scenes) != len(idx.scenes) || len(r.Transcript) != len(idx.segs) {
+		return false
+	}
+	return (len(r.Scenes) == 0 || &r.Scenes[0] == idx.scenePtr) &&
+		(len(r.Transcript) == 0 || &r.Transcript[0] == idx.segPtr)
+}
+
+// SceneAt returns the scene playing at t seconds, from its StartTime,
+// inclusive, to its EndTime, exclusive. The bool is false past the last scene
+// or in a gap between scenes.
+func (idx *ResultIndex) SceneAt(t float64) (Scene, bool) {
+	i := sort.Search(len(idx.sceneEnds), func(i int) bool { return idx.sceneEnds[i] > t })
+	if t < 0 || i == len(idx.scenes) || idx.scenes[i].StartTime > t {
+		return Scene{}, false
+	}
+	return idx.scenes[i], true
+}
+
+// TranscriptAt returns the segments spoken at t seconds, from StartTime
+// inclusive to EndTime exclusive, ordered by StartTime.
+func (idx *ResultIndex) TranscriptAt(t float64) []TranscriptSegment {
+	return idx.segments(t, func(s TranscriptSegment) bool { return s.StartTime <= t && s.EndTime > t })
+}
+
+// Between returns the scenes and segments overlapping [start, end] in
+// seconds, matching ScenesInRange and TranscriptInRange. Segments are ordered
+// by StartTime.
+func (idx *ResultIndex) Between(start, end float64) ([]Scene, []TranscriptSegment) {
+	var scenes []Scene
+	for i := sort.SearchFloat64s(idx.sceneEnds, start); i < len(idx.scenes) && idx.scenes[i].StartTime <= end; i++ {
+		scenes = append(scenes, idx.scenes[i])
+	}
+	segs := idx.segments(end, func(s TranscriptSegment) bool { return s.EndTime >= start && s.StartTime <= end })
+	return scenes, segs
+}
+
+// SceneAtD is SceneAt with t as a Duration.
+func (idx *ResultIndex) SceneAtD(t time.Duration) (Scene, bool) {
+	return idx.SceneAt(t.Seconds())
+}
+
+// TranscriptAtD is TranscriptAt with t as a Duration.
+func (idx *ResultIndex) TranscriptAtD(t time.Duration) []TranscriptSegment {
+	return idx.TranscriptAt(t.Seconds())
+}
+
+// BetweenD is Between with the bounds as Durations.
+func (idx *ResultIndex) BetweenD(start, end time.Duration) ([]Scene, []TranscriptSegment) {
+	return idx.Between(start.Seconds(), end.Seconds())
+}
+
+// segments returns the segments starting at or before t that satisfy keep.
+// Walking back from the last such segment stops once no earlier segment can
+// still be running, so only segments near t are visited.
+func (idx *ResultIndex) segments(t float64, keep func(TranscriptSegment) bool) []TranscriptSegment {
+	hi := sort.Search(len(idx.starts), func(i int) bool { return idx.starts[i] > t })
+	var out []TranscriptSegment
+	for i := hi - 1; i >= 0 && keep(TranscriptSegment{StartTime: idx.starts[i], EndTime: idx.maxEnd[i]}); i-- {
+		if keep(idx.segs[i]) {
+			out = append(out, idx.segs[i])
+		}
+	}
+	for a, b := 0, len(out)-1; a < b; a, b = a+1, b-1 {
+		out[a], out[b] = out[b], out[a]
+	}
+	return out
+}
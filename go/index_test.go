@@ -0,0 +1,151 @@
+package framequery
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResultIndexDuration(t *testing.T) {
+	r := &ProcessingResult{
+		Scenes:     []Scene{{Description: "a", EndTime: 10}, {Description: "b", EndTime: 20}},
+		Transcript: []TranscriptSegment{{StartTime: 9, EndTime: 12, Text: "x"}},
+	}
+	idx := r.Index()
+	if s, ok := idx.SceneAtD(15 * time.Second); !ok || s.Description != "b" {
+		t.Errorf("SceneAtD(15s) = %+v, %v", s, ok)
+	}
+	if segs := idx.TranscriptAtD(9500 * time.Millisecond); len(segs) != 1 {
+		t.Errorf("TranscriptAtD(9.5s) = %v", segs)
+	}
+	scenes, segs := idx.BetweenD(11*time.Second, 12*time.Second)
+	if len(scenes) != 1 || scenes[0].Description != "b" || len(segs) != 1 {
+		t.Errorf("BetweenD(11s, 12s) = %v, %v", scenes, segs)
+	}
+}
+
+func TestSceneStartsWithGaps(t *testing.T) {
+	// The API sent startTs, leaving gaps between scenes; "c" has none and
+	// starts where "b" ends
+	job, err := decodeJob([]byte(`{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":40,"scenes":[
+		{"description":"a","startTs":2,"endTs":10,"objects":["car"]},
+		{"description":"b","startTs":15,"endTs":20,"objects":["car"]},
+		{"description":"c","endTs":30,"objects":["dog"]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := job.result()
+	idx := r.Index()
+
+	for _, tt := range []struct {
+		at   float64
+		want string // "" for no scene
+	}{{1, ""}, {2, "a"}, {9.9, "a"}, {12, ""}, {15, "b"}, {20, "c"}, {29, "c"}, {30, ""}} {
+		got := ""
+		if s, ok := idx.SceneAt(tt.at); ok {
+			got = s.Description
+		}
+		if got != tt.want {
+			t.Errorf("SceneAt(%v) = %q, want %q", tt.at, got, tt.want)
+		}
+	}
+	for _, tt := range []struct {
+		start, end float64
+		want       string
+	}{{0, 1, ""}, {11, 14, ""}, {11, 15, "b"}, {0, 40, "abc"}, {10, 20, "abc"}} {
+		names := func(scenes []Scene) string {
+			var b strings.Builder
+			for _, s := range scenes {
+				b.WriteString(s.Description)
+			}
+			return b.String()
+		}
+		inRange := names(r.ScenesInRange(tt.start, tt.end))
+		between, _ := idx.Between(tt.start, tt.end)
+		if inRange != tt.want || names(between) != tt.want {
+			t.Errorf("[%v, %v]: ScenesInRange %q, Between %q; want %q", tt.start, tt.end, inRange, names(between), tt.want)
+		}
+	}
+
+	if got, want := r.ObjectTimeline("car"), []TimeRange{{2, 10}, {15, 20}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectTimeline(car) = %v, want %v", got, want)
+	}
+	var vtt strings.Builder
+	if err := r.WriteChaptersVTT(&vtt); err != nil {
+		t.Fatal(err)
+	}
+	for _, cue := range []string{"00:00:02.000 --> 00:00:10.000\na", "00:00:15.000 --> 00:00:20.000\nb", "00:00:20.000 --> 00:00:30.000\nc"} {
+		if !strings.Contains(vtt.String(), cue) {
+			t.Errorf("chapters missing %q:\n%s", cue, vtt.String())
+		}
+	}
+}
+
+func TestResultIndexConcurrent(t *testing.T) {
+	r := benchResult()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if segs := r.Index().TranscriptAt(float64(i)*2 + 1); len(segs) != 1 {
+					t.Errorf("TranscriptAt(%d) = %d segments, want 1", i*2+1, len(segs))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	r.Transcript = r.Transcript[:10]
+	if segs := r.Index().TranscriptAt(50); len(segs) != 0 {
+		t.Errorf("after truncating, TranscriptAt(50) = %v, want none", segs)
+	}
+}
+
+// benchResult is a 50k-segment transcript of two-second segments, one scene
+// per ten segments.
+func benchResult() *ProcessingResult {
+	r := &ProcessingResult{}
+	for i := 0; i < 50000; i++ {
+		start := float64(i) * 2
+		r.Transcript = append(r.Transcript, TranscriptSegment{StartTime: start, EndTime: start + 2.5, Text: "word"})
+		if i%10 == 9 {
+			r.Scenes = append(r.Scenes, Scene{EndTime: start + 2})
+		}
+	}
+	return r
+}
+
+func BenchmarkTranscriptLookup(b *testing.B) {
+	r := benchResult()
+	end := r.Transcript[len(r.Transcript)-1].EndTime
+	b.Run("naive", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			t := math.Mod(float64(i)*7.919, end)
+			r.TranscriptInRange(t, t)
+		}
+	})
+	b.Run("indexed", func(b *testing.B) {
+		r.Index() // built once, outside the timing
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			t := math.Mod(float64(i)*7.919, end)
+			r.Index().Between(t, t)
+		}
+	})
+}
+
+func BenchmarkIndexParallel(b *testing.B) {
+	r := benchResult()
+	r.Index()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			r.Index().TranscriptAt(math.Mod(float64(i)*7.919, 100000))
+		}
+	})
+}
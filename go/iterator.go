@@ -0,0 +1,118 @@
+package framequery
+
+import (
+	"context"
+	"fmt"
+)
+
+// JobIterator walks every job matching a ListJobsOptions, fetching pages as
+// needed. Use it like bufio.Scanner:
+//
+//	it := client.ListJobsAll(ctx, nil)
+//	for it.Next() {
+//		job := it.Job()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type JobIterator struct {
+	c    *Client
+	ctx  context.Context
+	opts ListJobsOptions
+	jobs []Job
+	job  Job
+	err  error
+	seen map[string]bool
+	more bool
+}
+
+// ListJobsAll returns an iterator over all jobs matching opts, following
+// NextCursor across pages. opts.Limit sets the page size; opts.Cursor, if set,
+// is where iteration starts.
+func (c *Client) ListJobsAll(ctx context.Context, opts *ListJobsOptions) *JobIterator {
+	it := &JobIterator{c: c, ctx: ctx, seen: map[string]bool{}, more: true}
+	if opts != nil {
+		it.opts = *opts
+	}
+	if it.opts.Cursor != "" {
+		it.seen[it.opts.Cursor] = true
+	}
+	return it
+}
+
+// Next advances to the next job. It returns false when there are no more jobs,
+// the context is done, or a request fails; check Err afterwards.
+func (it *JobIterator) Next() bool {
+	for len(it.jobs) == 0 {
+		if !it.more {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.stop(err)
+			return false
+		}
+		page, err := it.c.ListJobs(it.ctx, &it.opts)
+		if err != nil {
+			it.stop(err)
+			return false
+		}
+		it.jobs = page.Jobs
+		it.more = page.HasMore()
+		if !it.more {
+			continue
+		}
+		// A server bug could hand back the same cursor forever. Yield what
+		// this page has, then stop.
+		if it.seen[page.NextCursor] {
+			it.stop(fmt.Errorf("framequery: list jobs returned repeated cursor %q", page.NextCursor))
+			continue
+		}
+		it.seen[page.NextCursor] = true
+		it.opts.Cursor = page.NextCursor
+	}
+	it.job, it.jobs = it.jobs[0], it.jobs[1:]
+	return true
+}
+
+// Job returns the job at the current position.
+func (it *JobIterator) Job() Job {
+	return it.job
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *JobIterator) Err() error {
+	return it.err
+}
+
+func (it *JobIterator) stop(err error) {
+	it.err = err
+	it.more = false
+}
+
+// ForEachJobPage calls fn with each page of jobs matching opts, fetching the
+// next page only after fn returns. It stops and returns fn's error if fn
+// fails, ctx's error if ctx is done between pages, and ErrMaxPagesExceeded
+// (wrapped) if more pages remain after opts.MaxPages.
+func (c *Client) ForEachJobPage(ctx context.Context, opts *ListJobsOptions, fn func(*JobPage) error) error {
+	var o ListJobsOptions
+	if opts != nil {
+		o = *opts
+	}
+	for n := 1; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := c.ListJobs(ctx, &o)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if !page.HasMore() {
+			return nil
+		}
+		if o.MaxPages > 0 && n >= o.MaxPages {
+			return fmt.Errorf("framequery: stopped after %d pages: %w", n, ErrMaxPagesExceeded)
+		}
+		o.Cursor = page.NextCursor
+	}
+}
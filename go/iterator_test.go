@@ -0,0 +1,119 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListJobsAll(t *testing.T) {
+	srv := newJobsServer(t, 7, StatusVisionCompleted)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	var got []string
+	it := c.ListJobsAll(context.Background(), &ListJobsOptions{Limit: 3})
+	for it.Next() {
+		got = append(got, it.Job().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"j1", "j2", "j3", "j4", "j5", "j6", "j7"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("jobs = %v, want %v", got, want)
+	}
+	if len(srv.lists) != 3 {
+		t.Errorf("%d pages fetched, want 3", len(srv.lists))
+	}
+	for i, q := range srv.lists {
+		if q.Get("limit") != "3" {
+			t.Errorf("page %d limit = %q, want 3", i+1, q.Get("limit"))
+		}
+	}
+}
+
+func TestListJobsAllRepeatedCursor(t *testing.T) {
+	srv := newJobsServer(t, 7, StatusVisionCompleted)
+	defer srv.Close()
+	srv.repeatCursor = true
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	n := 0
+	it := c.ListJobsAll(context.Background(), &ListJobsOptions{Limit: 3})
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err == nil || !strings.Contains(err.Error(), "repeated cursor") {
+		t.Errorf("err = %v, want a repeated cursor error", it.Err())
+	}
+	if n != 6 || len(srv.lists) != 2 {
+		t.Errorf("%d jobs from %d pages, want 6 from 2", n, len(srv.lists))
+	}
+}
+
+func TestListJobsAllErrors(t *testing.T) {
+	srv := newJobsServer(t, 7, StatusVisionCompleted)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := c.ListJobsAll(ctx, &ListJobsOptions{Limit: 3})
+	for it.Next() {
+		cancel()
+	}
+	if it.Err() != context.Canceled || len(srv.lists) != 1 {
+		t.Errorf("err = %v after %d pages, want context.Canceled after 1", it.Err(), len(srv.lists))
+	}
+
+	it = New("k", WithBaseURL(srv.URL+"/missing"), WithMaxRetries(0)).ListJobsAll(context.Background(), nil)
+	if it.Next() || !IsNotFoundError(it.Err()) {
+		t.Errorf("err = %v, want the API's not-found error", it.Err())
+	}
+}
+
+func TestForEachJobPage(t *testing.T) {
+	srv := newJobsServer(t, 7, StatusVisionCompleted)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	var sizes []int
+	err := c.ForEachJobPage(ctx, &ListJobsOptions{Limit: 3}, func(p *JobPage) error {
+		if len(srv.lists) != len(sizes)+1 {
+			t.Errorf("page %d: %d pages fetched before fn returned", len(sizes)+1, len(srv.lists))
+		}
+		sizes = append(sizes, len(p.Jobs))
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(sizes, []int{3, 3, 1}) {
+		t.Errorf("pages of %v, err = %v; want 3, 3, 1", sizes, err)
+	}
+
+	srv.lists = nil
+	stop := errors.New("enough")
+	pages := 0
+	err = c.ForEachJobPage(ctx, &ListJobsOptions{Limit: 3}, func(*JobPage) error {
+		if pages++; pages == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || len(srv.lists) != 2 {
+		t.Errorf("err = %v after %d pages, want fn's error after 2", err, len(srv.lists))
+	}
+
+	srv.lists = nil
+	err = c.ForEachJobPage(ctx, &ListJobsOptions{Limit: 3, MaxPages: 2}, func(*JobPage) error { return nil })
+	if !errors.Is(err, ErrMaxPagesExceeded) || len(srv.lists) != 2 {
+		t.Errorf("err = %v after %d pages, want ErrMaxPagesExceeded after 2", err, len(srv.lists))
+	}
+
+	srv.lists = nil
+	err = c.ForEachJobPage(ctx, &ListJobsOptions{Limit: 4, MaxPages: 2}, func(*JobPage) error { return nil })
+	if err != nil {
+		t.Errorf("exactly MaxPages pages: err = %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package framequery
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// mergeCursorPrefix marks a NextCursor produced by a multi-status listing.
+const mergeCursorPrefix = "m1."
+
+// mergeState is one status's position in a merged listing: the page at Cursor,
+// minus the first Skip jobs already returned.
+type mergeState struct {
+	Status JobStatus `json:"s"`
+	Cursor string    `json:"c,omitempty"`
+	Skip   int       `json:"k,omitempty"`
+	Done   bool      `json:"d,omitempty"`
+}
+
+// statusSet returns Status and Statuses combined, without duplicates. Nil-safe.
+func (o *ListJobsOptions) statusSet() []JobStatus {
+	if o == nil {
+		return nil
+	}
+	var out []JobStatus
+	for _, s := range append([]JobStatus{o.Status}, o.Statuses...) {
+		if s != "" && !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// listJobsMerged lists jobs in any of several statuses by listing each status
+// separately and merging the pages newest first. A job is only returned once
+// no status could still have a newer one on a later page, so the merged order
+// is stable across pages. NextCursor encodes every status's position.
+func (c *Client) listJobsMerged(ctx context.Context, opts *ListJobsOptions, statuses []JobStatus, limit int, cursor string) (*JobPage, error) {
+	if cursor == "" {
+		cursor = opts.Cursor
+	}
+	states, err := decodeMergeCursor(cursor, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]*JobPage, len(states))
+	for i, st := range states {
+		if st.Done {
+			continue
+		}
+		sub := *opts
+		sub.Status, sub.Statuses, sub.Cursor = st.Status, nil, ""
+		p, err := c.listJobsPage(ctx, &sub, limit, st.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if st.Skip > 0 {
+			// Seen before; its decode errors were already reported
+			p.DecodeErrors = nil
+		}
+		if st.Skip < len(p.Jobs) {
+			p.Jobs = p.Jobs[st.Skip:]
+		} else {
+			p.Jobs = nil
+		}
+		pages[i] = p
+	}
+
+	// Jobs older than the last one fetched for a status with more pages might
+	// sort after that status's next page, so hold them back. A status with an
+	// empty page but more to come bounds nothing yet; just advance it.
+	var bound time.Time
+	stalled := false
+	for i, p := range pages {
+		if p == nil || !p.HasMore() {
+			continue
+		}
+		if len(p.Jobs) == 0 {
+			states[i] = mergeState{Status: states[i].Status, Cursor: p.NextCursor}
+			stalled = true
+			continue
+		}
+		if last := createdTime(&p.Jobs[len(p.Jobs)-1]); last.After(bound) {
+			bound = last
+		}
+	}
+
+	type candidate struct {
+		job Job
+		src int
+	}
+	var cands []candidate
+	if !stalled {
+		for i, p := range pages {
+			if p == nil {
+				continue
+			}
+			for _, j := range p.Jobs {
+				if !createdTime(&j).Before(bound) {
+					cands = append(cands, candidate{j, i})
+				}
+			}
+		}
+	}
+	sort.SliceStable(cands, func(a, b int) bool {
+		return createdTime(&cands[a].job).After(createdTime(&cands[b].job))
+	})
+	if limit > 0 && len(cands) > limit {
+		cands = cands[:limit]
+	}
+
+	page := &JobPage{RequestedLimit: limit, client: c, opts: *opts}
+	for _, p := range pages {
+		if p != nil {
+			page.DecodeErrors = append(page.DecodeErrors, p.DecodeErrors...)
+		}
+	}
+	taken := make([]int, len(states))
+	for _, cd := range cands {
+		page.Jobs = append(page.Jobs, cd.job)
+		taken[cd.src]++
+	}
+	done := true
+	for i, p := range pages {
+		switch {
+		case p == nil:
+		case taken[i] == len(p.Jobs) && !p.HasMore():
+			states[i].Done = true
+		case taken[i] == len(p.Jobs) && len(p.Jobs) > 0:
+			states[i].Cursor, states[i].Skip = p.NextCursor, 0
+		default:
+			states[i].Skip += taken[i]
+		}
+		done = done && states[i].Done
+	}
+	if !done {
+		b, _ := json.Marshal(states)
+		page.NextCursor = mergeCursorPrefix + base64.RawURLEncoding.EncodeToString(b)
+	}
+	return page, nil
+}
+
+func decodeMergeCursor(cursor string, statuses []JobStatus) ([]mergeState, error) {
+	if cursor == "" {
+		states := make([]mergeState, len(statuses))
+		for i, s := range statuses {
+			states[i].Status = s
+		}
+		return states, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(cursor, mergeCursorPrefix))
+	var states []mergeState
+	if err == nil && strings.HasPrefix(cursor, mergeCursorPrefix) {
+		err = json.Unmarshal(b, &states)
+	}
+	if err != nil || len(states) == 0 {
+		return nil, fmt.Errorf("framequery: cursor %q is not from a multi-status listing", cursor)
+	}
+	return states, nil
+}
+
+// createdTime parses CreatedAt, or returns the zero time.
+func createdTime(j *Job) time.Time {
+	t, _ := time.Parse(time.RFC3339, j.CreatedAt)
+	return t
+}
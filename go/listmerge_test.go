@@ -0,0 +1,66 @@
+package framequery
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListJobsMerged(t *testing.T) {
+	srv := newJobsServer(t, 30, StatusQueued, StatusVisionCompleted, StatusFailed, StatusQueued)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	var want []string
+	for _, j := range srv.jobs {
+		if j.Status == StatusQueued || j.Status == StatusFailed {
+			want = append(want, j.ID)
+		}
+	}
+
+	var got []string
+	page, err := c.ListJobs(context.Background(), &ListJobsOptions{Limit: 4, Statuses: []JobStatus{StatusQueued, StatusFailed}})
+	for err == nil {
+		if len(page.Jobs) > 4 {
+			t.Errorf("page of %d jobs, want at most 4", len(page.Jobs))
+		}
+		for _, j := range page.Jobs {
+			got = append(got, j.ID)
+		}
+		if !page.HasMore() {
+			break
+		}
+		if !strings.HasPrefix(page.NextCursor, mergeCursorPrefix) {
+			t.Fatalf("NextCursor %q is not a merged cursor", page.NextCursor)
+		}
+		page, err = page.NextPage(context.Background())
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged jobs = %v, want %v", got, want)
+	}
+	for _, q := range srv.lists {
+		if st := q.Get("status"); st != "QUEUED" && st != "FAILED" {
+			t.Errorf("list query %v, want one status per request", q)
+		}
+	}
+}
+
+func TestListJobsStatusesDeduplicated(t *testing.T) {
+	srv := newJobsServer(t, 5, StatusQueued, StatusFailed)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+
+	page, err := c.ListJobs(context.Background(), &ListJobsOptions{Status: StatusFailed, Statuses: []JobStatus{StatusFailed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.lists) != 1 || srv.lists[0].Get("status") != "FAILED" || len(page.Jobs) != 2 {
+		t.Errorf("%d requests (%v) returning %d jobs, want one FAILED listing of 2", len(srv.lists), srv.lists, len(page.Jobs))
+	}
+	if strings.HasPrefix(page.NextCursor, mergeCursorPrefix) {
+		t.Errorf("NextCursor %q: a single status shouldn't merge", page.NextCursor)
+	}
+}
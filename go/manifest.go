@@ -0,0 +1,71 @@
+package framequery
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// ProcessingManifest records the options a job was created with, so a result
+// can be traced back to exactly what produced it. Options holds the
+// job-creation request body as sent. Overridden lists option keys whose value
+// in the job payload differs from what was sent, i.e. the server changed them.
+type ProcessingManifest struct {
+	SDKVersion  string         `json:"sdkVersion"`
+	BaseURL     string         `json:"baseUrl"`
+	SubmittedAt time.Time      `json:"submittedAt"`
+	Options     map[string]any `json:"options"`
+	Overridden  []string       `json:"overridden,omitempty"`
+}
+
+func newManifest(baseURL string, body map[string]any) *ProcessingManifest {
+	return &ProcessingManifest{
+		SDKVersion:  version,
+		BaseURL:     baseURL,
+		SubmittedAt: time.Now().UTC(),
+		Options:     copyMap(body),
+	}
+}
+
+// withOverrides returns a copy of m with Overridden filled in from a job payload.
+func (m *ProcessingManifest) withOverrides(raw map[string]any) *ProcessingManifest {
+	if m == nil {
+		return nil
+	}
+	out := *m
+	out.Overridden = nil
+	for k, sent := range m.Options {
+		got, ok := raw[k]
+		if !ok {
+			continue
+		}
+		// Compare JSON encodings; raw values are decoded generically
+		a, errA := json.Marshal(sent)
+		b, errB := json.Marshal(got)
+		if errA == nil && errB == nil && string(a) != string(b) {
+			out.Overridden = append(out.Overridden, k)
+		}
+	}
+	sort.Strings(out.Overridden)
+	return &out
+}
+
+// decodeManifest reads a manifest stored by MarshalJSON, if present.
+func decodeManifest(raw map[string]any) *ProcessingManifest {
+	v, ok := raw[manifestKey]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m ProcessingManifest
+	if json.Unmarshal(b, &m) != nil {
+		return nil
+	}
+	return &m
+}
+
+// manifestKey is where MarshalJSON stores the manifest. It is SDK-side only.
+const manifestKey = "sdkManifest"
@@ -0,0 +1,142 @@
+// Package metrics adds Prometheus instrumentation to the FrameQuery client. It
+// lives in its own module so the core SDK stays free of Prometheus
+// dependencies.
+//
+//	client = fqmetrics.NewInstrumentedClient(client, prometheus.DefaultRegisterer)
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	framequery "github.com/framequery/framequery-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type collectors struct {
+	duration    *prometheus.HistogramVec
+	errors      *prometheus.CounterVec
+	activePolls prometheus.Gauge
+}
+
+// NewInstrumentedClient returns a clone of c that records, in reg:
+//
+//   - framequery_api_request_duration_seconds{method, endpoint, status_code}:
+//     latency of every HTTP request, including retries; status_code is
+//     "error" when no response arrived
+//   - framequery_api_errors_total{type}: failed requests by kind: network,
+//     canceled, rate_limit, auth, permission, not_found, client, or server
+//   - framequery_active_polls: jobs currently being polled to completion
+//
+// A nil reg means prometheus.DefaultRegisterer. Several clients may share a
+// registry; they share its metrics.
+func NewInstrumentedClient(c *framequery.Client, reg prometheus.Registerer) *framequery.Client {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	m := &collectors{
+		duration: register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "framequery_api_request_duration_seconds",
+			Help:    "Latency of FrameQuery API requests.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "endpoint", "status_code"})),
+		errors: register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "framequery_api_errors_total",
+			Help: "FrameQuery API requests that failed, by kind.",
+		}, []string{"type"})),
+		activePolls: register(reg, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "framequery_active_polls",
+			Help: "FrameQuery jobs currently being polled to completion.",
+		})),
+	}
+	return c.Clone(
+		framequery.WithMiddleware(framequery.InterceptorFunc(m.observe)),
+		framequery.WithPollHook(func(string) func() {
+			m.activePolls.Inc()
+			return m.activePolls.Dec
+		}),
+	)
+}
+
+func (m *collectors) observe(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	m.duration.WithLabelValues(req.Method, endpoint(req.URL.Path), code).Observe(time.Since(start).Seconds())
+	if kind := errorType(resp, err); kind != "" {
+		m.errors.WithLabelValues(kind).Inc()
+	}
+	return resp, err
+}
+
+// register registers c, or returns the equal collector already in reg.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+// errorType classifies a failed request, or returns "" for a success.
+func errorType(resp *http.Response, err error) string {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case err != nil:
+		return "network"
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "rate_limit"
+	case resp.StatusCode == http.StatusUnauthorized:
+		return "auth"
+	case resp.StatusCode == http.StatusForbidden:
+		return "permission"
+	case resp.StatusCode == http.StatusNotFound:
+		return "not_found"
+	case resp.StatusCode >= 500:
+		return "server"
+	case resp.StatusCode >= 400:
+		return "client"
+	}
+	return ""
+}
+
+// endpoint turns a request path into a low-cardinality label, such as
+// /jobs/{id}/cancel. Requests outside the API, like signed upload URLs and
+// key frame downloads, are "external".
+func endpoint(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, p := range parts {
+		switch p {
+		case "quota":
+			return "/quota"
+		case "jobs":
+			rest := parts[i+1:]
+			if len(rest) == 0 {
+				return "/jobs"
+			}
+			switch rest[0] {
+			case "from-url", "batch", "counts":
+				return "/jobs/" + rest[0]
+			}
+			out := "/jobs/{id}"
+			if len(rest) > 1 {
+				out += "/" + rest[1]
+			}
+			return out
+		}
+	}
+	return "external"
+}
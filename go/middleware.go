@@ -0,0 +1,136 @@
+package framequery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Interceptor wraps every HTTP request the client makes. Call next.RoundTrip
+// to continue the chain; return without calling it to short-circuit.
+type Interceptor interface {
+	RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error)
+}
+
+// InterceptorFunc adapts a function to the Interceptor interface.
+type InterceptorFunc func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// RoundTrip calls f(req, next).
+func (f InterceptorFunc) RoundTrip(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	return f(req, next)
+}
+
+// WithMiddleware adds interceptors around the HTTP transport. The first
+// interceptor is outermost. May be given more than once; calls accumulate.
+func WithMiddleware(i ...Interceptor) Option {
+	return func(c *Client) {
+		// Capped so a Clone's additions don't write into the parent's slice
+		c.interceptors = append(c.interceptors[:len(c.interceptors):len(c.interceptors)], i...)
+	}
+}
+
+// WithPollHook calls hook when the client starts polling a job to completion,
+// as Process, ProcessURL, WaitForJob, and the other waiting calls do, and
+// calls the func it returns, if any, when polling stops. May be given more
+// than once; hooks accumulate.
+func WithPollHook(hook func(jobID string) (done func())) Option {
+	return func(c *Client) {
+		c.pollHooks = append(c.pollHooks[:len(c.pollHooks):len(c.pollHooks)], hook)
+	}
+}
+
+// WithCreateJobHook calls hook with the final job-creation body, after the
+// typed options and ExtraFields are merged, just before SubmitURL, Upload,
+// and CloneJob send it. path is the endpoint, such as "/jobs". The hook may
+// inspect or change body; an error aborts the call. May be given more than
+// once; hooks run in order.
+func WithCreateJobHook(hook func(ctx context.Context, path string, body map[string]any) error) Option {
+	return func(c *Client) {
+		c.createHooks = append(c.createHooks[:len(c.createHooks):len(c.createHooks)], hook)
+	}
+}
+
+// runCreateHooks passes a job-creation body to each WithCreateJobHook hook.
+func (c *Client) runCreateHooks(ctx context.Context, path string, body map[string]any) error {
+	for _, hook := range c.createHooks {
+		if err := hook(ctx, path, body); err != nil {
+			return fmt.Errorf("framequery: create job hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// requestIDKey is the context key WithRequestID stores the ID under.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a request or trace ID. A
+// client configured with WithRequestIDHeader sends it on API requests made
+// with the returned context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the ID set by WithRequestID, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithRequestIDHeader sends the ID set by WithRequestID on each API request,
+// as the named header (e.g. "X-Request-Id" or "X-Correlation-Id"). Requests
+// whose context has no ID are sent without it. Uploads to presigned storage
+// URLs never carry it.
+func WithRequestIDHeader(header string) Option {
+	return func(c *Client) { c.requestIDHeader = http.CanonicalHeaderKey(header) }
+}
+
+// setRequestID copies the context's request ID onto req, if configured.
+func (c *Client) setRequestID(req *http.Request) {
+	if c.requestIDHeader == "" {
+		return
+	}
+	if id, ok := RequestIDFromContext(req.Context()); ok {
+		req.Header.Set(c.requestIDHeader, id)
+	}
+}
+
+// LoggingInterceptor writes one line per request to w: method, URL, status, and latency.
+// As with WithDebug, the query of a presigned upload URL is not logged.
+func LoggingInterceptor(w io.Writer) Interceptor {
+	var mu sync.Mutex
+	return InterceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(w, "framequery: %s %s error: %v (%s)\n", req.Method, redactURL(req), err, elapsed)
+		} else {
+			fmt.Fprintf(w, "framequery: %s %s %d (%s)\n", req.Method, redactURL(req), resp.StatusCode, elapsed)
+		}
+		return resp, err
+	})
+}
+
+// HeaderInjectorInterceptor sets header key to val on every request.
+func HeaderInjectorInterceptor(key, val string) Interceptor {
+	return InterceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		req = req.Clone(req.Context())
+		req.Header.Set(key, val)
+		return next.RoundTrip(req)
+	})
+}
+
+// interceptorTransport runs one interceptor in front of next.
+type interceptorTransport struct {
+	interceptor Interceptor
+	next        http.RoundTripper
+}
+
+func (t *interceptorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.interceptor.RoundTrip(req, t.next)
+}
@@ -1,15 +1,115 @@
 package framequery
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 // Scene is a single detected scene with a description, end timestamp, and tagged objects.
+//
+// Objects always holds the object labels. When the API reports per-object
+// confidence, Detections holds the full entries as well.
 type Scene struct {
 	Description string   `json:"description"`
-	EndTime     float64  `json:"endTs"`
-	Objects     []string `json:"objects"`
+	Summary     string   `json:"summary,omitempty"`  // set with IncludeSummary
+	Keywords    []string `json:"keywords,omitempty"` // set with IncludeKeywords
+	// StartTime is the API's startTs when it sends one, and otherwise the
+	// previous scene's EndTime (0 for the first). Set on results.
+	StartTime   float64           `json:"startTs,omitempty"`
+	EndTime     float64           `json:"endTs"`
+	KeyFrameURL string            `json:"keyFrameUrl"`
+	Objects     []string          `json:"objects"`
+	Detections  []ObjectDetection `json:"-"`
+	// ObjectTracks gives per-object time ranges when the API provides them.
+	ObjectTracks []ObjectTrack `json:"objectTracks,omitempty"`
+	MergedFrom   []int         `json:"mergedFrom,omitempty"` // original indices, set by MergeSimilarScenes
+}
+
+// ObjectDetection is one object tagged in a scene.
+type ObjectDetection struct {
+	Label      string  `json:"label"`
+	Confidence float64 `json:"confidence"`
+}
+
+// TimeRange is a span in seconds from the start of the video.
+type TimeRange struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// ObjectTrack is where one object appears within a scene.
+type ObjectTrack struct {
+	Label      string      `json:"label"`
+	Spans      []TimeRange `json:"spans"`
+	Confidence float64     `json:"confidence"`
+}
+
+// ObjectLabels returns the scene's object labels.
+func (s Scene) ObjectLabels() []string {
+	if s.Objects == nil && s.Detections != nil {
+		labels := make([]string, len(s.Detections))
+		for i, d := range s.Detections {
+			labels[i] = d.Label
+		}
+		return labels
+	}
+	return s.Objects
+}
+
+// MarshalJSON emits objects in the API's shape: detection entries when the
+// scene has them, plain labels otherwise.
+func (s Scene) MarshalJSON() ([]byte, error) {
+	type plain Scene
+	out := struct {
+		plain
+		Objects any `json:"objects"`
+	}{plain: plain(s), Objects: s.Objects}
+	if s.Detections != nil {
+		out.Objects = s.Detections
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON accepts objects as labels, detection entries, or a mix, the
+// shapes MarshalJSON and the API produce. Entries that are neither are
+// skipped, and a field of the wrong type is left zero.
+func (s *Scene) UnmarshalJSON(b []byte) error {
+	type plain Scene
+	var in struct {
+		plain
+		Objects []json.RawMessage `json:"objects"`
+	}
+	if err := unmarshalLenient(b, &in); err != nil {
+		return err
+	}
+	*s = Scene(in.plain)
+	for _, o := range in.Objects {
+		switch {
+		case len(o) > 0 && o[0] == '"':
+			var label string
+			if json.Unmarshal(o, &label) == nil {
+				s.Objects = append(s.Objects, label)
+			}
+		case len(o) > 0 && o[0] == '{':
+			var d ObjectDetection
+			if json.Unmarshal(o, &d) == nil {
+				s.Objects = append(s.Objects, d.Label)
+				s.Detections = append(s.Detections, d)
+			}
+		}
+		// Anything else, like a bare number, is skipped
+	}
+	return nil
 }
 
 // TranscriptSegment is one timed chunk of the speech-to-text transcript.
@@ -17,25 +117,168 @@ type TranscriptSegment struct {
 	StartTime float64 `json:"StartTime"`
 	EndTime   float64 `json:"EndTime"`
 	Text      string  `json:"Text"`
+	Speaker   string  `json:"Speaker,omitempty"` // diarization label; set only with EnableDiarization
+}
+
+// segmentKeys lists the accepted keys for each segment field, canonical
+// first. Some pipeline versions send camelCase, or startTs/endTs like scenes.
+var segmentKeys = [...][]string{
+	{"StartTime", "startTime", "startTs"},
+	{"EndTime", "endTime", "endTs"},
+	{"Text", "text"},
+	{"Speaker", "speaker"},
+}
+
+// UnmarshalJSON accepts the canonical PascalCase keys and the camelCase and
+// startTs/endTs variants, preferring the canonical key when both are present.
+// A value of the wrong type is left zero.
+func (t *TranscriptSegment) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := unmarshalLenient(b, &m); err != nil {
+		return err
+	}
+	*t = TranscriptSegment{}
+	fields := [...]any{&t.StartTime, &t.EndTime, &t.Text, &t.Speaker}
+	for i, keys := range segmentKeys {
+		for _, k := range keys {
+			if v, ok := m[k]; ok {
+				_ = json.Unmarshal(v, fields[i])
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// StartMillis returns StartTime in whole milliseconds, rounded half-up.
+func (t TranscriptSegment) StartMillis() int64 { return secondsToMillis(t.StartTime) }
+
+// EndMillis returns EndTime in whole milliseconds, rounded half-up.
+func (t TranscriptSegment) EndMillis() int64 { return secondsToMillis(t.EndTime) }
+
+// StartMillis returns StartTime in whole milliseconds, rounded half-up.
+func (s Scene) StartMillis() int64 { return secondsToMillis(s.StartTime) }
+
+// EndMillis returns EndTime in whole milliseconds, rounded half-up.
+func (s Scene) EndMillis() int64 { return secondsToMillis(s.EndTime) }
+
+// StartD returns StartTime as a Duration, rounded to the millisecond.
+func (t TranscriptSegment) StartD() time.Duration { return secondsToDuration(t.StartTime) }
+
+// EndD returns EndTime as a Duration, rounded to the millisecond.
+func (t TranscriptSegment) EndD() time.Duration { return secondsToDuration(t.EndTime) }
+
+// EndD returns EndTime as a Duration, rounded to the millisecond.
+func (s Scene) EndD() time.Duration { return secondsToDuration(s.EndTime) }
+
+// StartD returns StartTime as a Duration, rounded to the millisecond.
+func (s Scene) StartD() time.Duration { return secondsToDuration(s.StartTime) }
+
+// Duration returns the scene's length in seconds.
+func (s Scene) Duration() float64 { return s.EndTime - s.StartTime }
+
+// DurationD returns Duration as a Duration, rounded to the millisecond.
+func (s Scene) DurationD() time.Duration { return s.EndD() - s.StartD() }
+
+// DurationD returns Duration as a Duration, rounded to the millisecond.
+func (r *ProcessingResult) DurationD() time.Duration { return secondsToDuration(r.Duration) }
+
+// ETAD returns ETASeconds as a Duration, rounded to the millisecond.
+func (j *Job) ETAD() time.Duration { return secondsToDuration(j.ETASeconds) }
+
+// ETADuration is ETAD under a longer name, for symmetry with ETA.
+func (j *Job) ETADuration() time.Duration { return j.ETAD() }
+
+// ETA returns the estimated completion time, counting ETASeconds from now,
+// so call it soon after fetching the job. It is the zero Time when the API
+// gave no estimate.
+func (j *Job) ETA() time.Time {
+	if j.ETASeconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(j.ETADuration())
 }
 
 // ProcessedData maps to the processedData field in the job JSON.
 type ProcessedData struct {
-	Length     float64             `json:"length"`
-	Scenes     []Scene            `json:"scenes"`
-	Transcript []TranscriptSegment `json:"transcript"`
+	Length           float64             `json:"length"`
+	DetectedLanguage string              `json:"detectedLanguage,omitempty"`
+	Summary          string              `json:"summary,omitempty"`
+	Keywords         []string            `json:"keywords,omitempty"`
+	Topics           []string            `json:"topics,omitempty"`
+	Scenes           []Scene             `json:"scenes"`
+	Transcript       []TranscriptSegment `json:"transcript"`
+}
+
+// VideoMetadata is technical metadata about the source video, as the API
+// probed it. Fields the API didn't report are zero.
+type VideoMetadata struct {
+	Width         int     `json:"width,omitempty"`
+	Height        int     `json:"height,omitempty"`
+	FPS           float64 `json:"fps,omitempty"`
+	Codec         string  `json:"codec,omitempty"`
+	Bitrate       int64   `json:"bitrate,omitempty"` // bits per second
+	AudioChannels int     `json:"audioChannels,omitempty"`
+}
+
+// UnmarshalJSON accepts fps as a number or as a rational string such as
+// "30000/1001", the form ffprobe reports. A field of the wrong type, or an
+// fps that parses as neither, is left zero.
+func (m *VideoMetadata) UnmarshalJSON(b []byte) error {
+	type plain VideoMetadata
+	var v struct {
+		plain
+		FPS json.RawMessage `json:"fps"`
+	}
+	if err := unmarshalLenient(b, &v); err != nil {
+		return err
+	}
+	*m = VideoMetadata(v.plain)
+	m.FPS, _ = parseFPS(v.FPS)
+	return nil
+}
+
+// parseFPS decodes a frame rate given as a number or a "num/den" string.
+func parseFPS(b json.RawMessage) (float64, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return 0, nil
+	}
+	var f float64
+	if err := json.Unmarshal(b, &f); err == nil {
+		return f, nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return 0, fmt.Errorf("fps: want a number or string, got %s", b)
+	}
+	num, den, ok := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("fps: %q is not a frame rate", s)
+	}
+	if !ok {
+		return n, nil
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil {
+		return 0, fmt.Errorf("fps: %q is not a frame rate", s)
+	}
+	if d == 0 {
+		return 0, nil
+	}
+	return n / d, nil
 }
 
 // AudioTrack describes an additional audio track attached to a job.
 type AudioTrack struct {
-	FileName              string `json:"fileName"`
-	URL                   string `json:"url,omitempty"`
-	DownloadToken         string `json:"downloadToken,omitempty"`
-	SyncMode              string `json:"syncMode,omitempty"`
-	OffsetMs              int    `json:"offsetMs,omitempty"`
-	Label                 string `json:"label,omitempty"`
-	PerChannelTranscription bool `json:"perChannelTranscription,omitempty"`
-	Channels              int    `json:"channels,omitempty"`
+	FileName                string `json:"fileName"`
+	URL                     string `json:"url,omitempty"`
+	DownloadToken           string `json:"downloadToken,omitempty"`
+	SyncMode                string `json:"syncMode,omitempty"`
+	OffsetMs                int    `json:"offsetMs,omitempty"`
+	Label                   string `json:"label,omitempty"`
+	PerChannelTranscription bool   `json:"perChannelTranscription,omitempty"`
+	Channels                int    `json:"channels,omitempty"`
 }
 
 // AudioTrackTranscript holds the transcript result for a single audio track.
@@ -49,44 +292,137 @@ type AudioTrackTranscript struct {
 	ErrorMessage string              `json:"errorMessage,omitempty"`
 }
 
+// Feature selects an analysis to run on a job. Jobs run every feature by default.
+type Feature string
+
+const (
+	FeatureScenes     Feature = "scenes"
+	FeatureTranscript Feature = "transcript"
+)
+
 // ProcessingResult is returned when a job reaches a terminal success state.
+// Features lists what the job was asked to produce; it is empty when all
+// features ran.
 type ProcessingResult struct {
 	JobID      string
-	Status     string
+	Status     JobStatus
 	Filename   string
 	Duration   float64
 	Scenes     []Scene
 	Transcript []TranscriptSegment
+	Features   []Feature
 	CreatedAt  string
-	Raw        map[string]any
+	// DetectedLanguage is the BCP-47 tag of the spoken language, when the API
+	// reports one.
+	DetectedLanguage string
+	// VideoSummary describes the whole video, when requested with
+	// IncludeSummary. (Summary is the log line from format.go.)
+	VideoSummary string
+	// Keywords and Topics describe the whole video, when requested with
+	// IncludeKeywords and IncludeTopics.
+	Keywords []string
+	Topics   []string
+	// VideoMetadata is nil if the API didn't report any.
+	VideoMetadata *VideoMetadata
+	Raw           map[string]any
+	// RawBody is the verbatim API response when CaptureRawResponse is set.
+	// RawBodySHA256 is its hex digest, set whenever the body was captured or streamed.
+	RawBody       []byte
+	RawBodySHA256 string
+	// Err is set by RecentResults when this job's result could not be fetched.
+	Err error
+	// Manifest is set by Process and ProcessURL.
+	Manifest *ProcessingManifest
+
+	index atomic.Value // *ResultIndex; see Index
+}
+
+// Requested reports whether f was requested for the job. An empty Scenes or
+// Transcript with Requested true means nothing was found.
+func (r *ProcessingResult) Requested(f Feature) bool {
+	if len(r.Features) == 0 {
+		return true
+	}
+	for _, v := range r.Features {
+		if v == f {
+			return true
+		}
+	}
+	return false
 }
 
 // Job tracks a video through the processing pipeline. Raw holds the full API response.
 type Job struct {
 	ID                   string
-	Status               string
+	Status               JobStatus
 	Filename             string
 	CreatedAt            string
 	ETASeconds           float64
 	AudioTrackCount      *int
 	AudioTracksCompleted *int
 	AudioTrackNames      []string
+	DisplayName          string
+	Metadata             map[string]string
+	Tags                 []string
+	ErrorMessage         string         // why the job failed, if it did
+	ErrorCode            string         // machine-readable failure code, when the API sends one
+	VideoMetadata        *VideoMetadata // nil until the API has probed the source
 	Raw                  map[string]any
+	RawBody              []byte // verbatim response, only with WithCaptureRawResponse
+	RawBodySHA256        string
+	Manifest             *ProcessingManifest // set by Upload
+	UploadStats          *UploadStats        // set by Upload with UploadOptions.Resumable, for single-PUT uploads
+
+	wire *jobWire // what Raw decoded to, if the job came from the API
 }
 
-// IsTerminal reports whether the job is done (VISION_COMPLETED, VIDEO_COMPLETED_NO_SCENES, or any FAILED status).
+// JobStatus is a job's processing state. A status the SDK doesn't know yet
+// passes through unchanged; convert with JobStatus(s) to compare a plain
+// string.
+type JobStatus string
+
+// Job statuses the API reports, roughly in processing order.
+const (
+	StatusPendingUpload         JobStatus = "PENDING_UPLOAD" // created, waiting for the file
+	StatusPendingFetch          JobStatus = "PENDING_FETCH"  // created from a URL, waiting to download it
+	StatusQueued                JobStatus = "QUEUED"         // waiting for a processing slot
+	StatusIngestProcessing      JobStatus = "INGEST_PROCESSING"
+	StatusIngestTranscoding     JobStatus = "INGEST_TRANSCODING"
+	StatusIngestCompleted       JobStatus = "INGEST_COMPLETED"
+	StatusVideoProcessing       JobStatus = "VIDEO_PROCESSING"
+	StatusVideoCompleted        JobStatus = "VIDEO_COMPLETED"
+	StatusVisionProcessing      JobStatus = "VISION_PROCESSING"
+	StatusVisionCompleted       JobStatus = "VISION_COMPLETED"          // done, with results
+	StatusCompletedNoScenes     JobStatus = "VIDEO_COMPLETED_NO_SCENES" // done, no scenes found
+	StatusFailed                JobStatus = "FAILED"
+	StatusFailedFetch           JobStatus = "FAILED_FETCH"
+	StatusIngestFailedTranscode JobStatus = "INGEST_FAILED_TRANSCODE"
+	StatusCancelled             JobStatus = "CANCELLED" // stopped by CancelJob
+	StatusExpired               JobStatus = "EXPIRED"   // results deleted under the retention policy
+)
+
+// StatusPartial is the ProcessingResult.Status of a result returned with
+// ErrPartialResult. Jobs themselves never report it.
+const StatusPartial JobStatus = "PARTIAL"
+
+// IsTerminal reports whether the job is done (VISION_COMPLETED, VIDEO_COMPLETED_NO_SCENES, CANCELLED, EXPIRED, or any FAILED status).
 func (j *Job) IsTerminal() bool {
-	return j.IsComplete() || j.IsFailed()
+	return stateOf(j.Status) != stateRunning
+}
+
+// IsCancelled reports whether the job was cancelled.
+func (j *Job) IsCancelled() bool {
+	return stateOf(j.Status) == stateCancelled
 }
 
 // IsComplete reports whether the job finished successfully (VISION_COMPLETED or VIDEO_COMPLETED_NO_SCENES).
 func (j *Job) IsComplete() bool {
-	return j.Status == "VISION_COMPLETED" || j.Status == "VIDEO_COMPLETED_NO_SCENES"
+	return stateOf(j.Status) == stateComplete
 }
 
 // IsFailed reports whether the job has failed (any status containing "FAILED").
 func (j *Job) IsFailed() bool {
-	return strings.Contains(j.Status, "FAILED")
+	return stateOf(j.Status) == stateFailed
 }
 
 // Result parses processedData from a completed job.
@@ -95,10 +431,39 @@ func (j *Job) Result() (*ProcessingResult, bool) {
 	if !j.IsComplete() {
 		return nil, false
 	}
-	if _, ok := j.Raw["processedData"]; !ok {
+	if !j.hasProcessedData() {
 		return nil, false
 	}
-	return parseResult(j.Raw), true
+	return j.result(), true
+}
+
+// sceneSpans returns a copy of scenes with deriveSceneStarts applied, for
+// helpers that need every scene's StartTime without touching the caller's.
+func sceneSpans(scenes []Scene) []Scene {
+	out := append([]Scene(nil), scenes...)
+	deriveSceneStarts(out)
+	return out
+}
+
+// deriveSceneStarts sorts scenes by EndTime and fills in each StartTime the
+// API didn't send from the previous scene's EndTime.
+func deriveSceneStarts(scenes []Scene) {
+	sort.SliceStable(scenes, func(a, b int) bool { return scenes[a].EndTime < scenes[b].EndTime })
+	for i := 1; i < len(scenes); i++ {
+		if scenes[i].StartTime == 0 {
+			scenes[i].StartTime = scenes[i-1].EndTime
+		}
+	}
+}
+
+// hasProcessedData reports whether the job payload included processedData,
+// from Raw, or from the typed decode when Raw was dropped.
+func (j *Job) hasProcessedData() bool {
+	if j.Raw == nil && j.wire != nil {
+		return j.wire.ProcessedData != nil
+	}
+	_, ok := j.Raw["processedData"]
+	return ok
 }
 
 // Quota holds the account's plan, included hours, credit balance, and reset date.
@@ -106,13 +471,55 @@ type Quota struct {
 	Plan                string  `json:"currentPlan"`
 	IncludedHours       float64 `json:"includedHours"`
 	CreditsBalanceHours float64 `json:"creditsBalanceHours"`
+	UsedHours           float64 `json:"usedHours"`
 	ResetDate           string  `json:"resetDate"`
 }
 
+// TotalAvailableHours returns included hours plus credit balance.
+func (q *Quota) TotalAvailableHours() float64 {
+	return q.IncludedHours + q.CreditsBalanceHours
+}
+
+// IsExhausted reports whether no processing hours remain.
+func (q *Quota) IsExhausted() bool {
+	return q.TotalAvailableHours() <= 0
+}
+
+// ResetTime parses ResetDate (RFC 3339 or YYYY-MM-DD).
+// Returns the zero time if ResetDate is empty or unparseable.
+func (q *Quota) ResetTime() time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, q.ResetDate); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // JobPage is one page from ListJobs. Use NextCursor to fetch the next page.
+//
+// Page size is not a reliable end-of-list signal: the server may return fewer
+// jobs than requested. ServerLimit is set when that happens while NextCursor is
+// non-empty, and holds the page size the server actually used.
 type JobPage struct {
-	Jobs       []Job
-	NextCursor string
+	Jobs           []Job
+	NextCursor     string
+	RequestedLimit int
+	ServerLimit    int
+	Truncated      bool // a FilenameContains scan stopped early; see ListJobs
+
+	// TotalCount and Limit are the match count and page size the server
+	// reports, 0 when absent. TotalCount doesn't reflect client-side
+	// filtering, and is not set on multi-status pages.
+	TotalCount int
+	Limit      int
+
+	// DecodeErrors lists malformed items skipped from this page, when
+	// ListJobsOptions.Strict is set.
+	DecodeErrors []*DecodeError
+
+	client *Client
+	opts   ListJobsOptions
 }
 
 // HasMore reports whether another page is available.
@@ -120,16 +527,103 @@ func (p *JobPage) HasMore() bool {
 	return p.NextCursor != ""
 }
 
-// ProcessOptions tunes polling behavior for Process and ProcessURL.
-// Defaults: 5s poll interval, 24h timeout.
+// maxFilterPages bounds how many pages one ListJobs call scans when
+// filtering by filename client-side.
+const maxFilterPages = 20
+
+// filterFilename drops jobs whose Filename doesn't contain sub, ignoring case.
+func (p *JobPage) filterFilename(sub string) {
+	sub = strings.ToLower(sub)
+	kept := p.Jobs[:0]
+	for _, j := range p.Jobs {
+		if strings.Contains(strings.ToLower(j.Filename), sub) {
+			kept = append(kept, j)
+		}
+	}
+	p.Jobs = kept
+}
+
+// filterCreated drops jobs outside the creation bounds, for servers that
+// ignore createdAfter/createdBefore. Jobs are listed newest first, so once one
+// predates CreatedAfter there is nothing more to fetch.
+func (p *JobPage) filterCreated(opts *ListJobsOptions) {
+	kept := p.Jobs[:0]
+	for i := range p.Jobs {
+		ok, passed := opts.inRange(&p.Jobs[i])
+		if passed && opts.newestFirst() {
+			p.NextCursor = ""
+		}
+		if ok {
+			kept = append(kept, p.Jobs[i])
+		}
+	}
+	p.Jobs = kept
+}
+
+// NextPage fetches the page after p with the same filters and limit. It
+// returns ErrNoMorePages when HasMore is false.
+func (p *JobPage) NextPage(ctx context.Context) (*JobPage, error) {
+	if !p.HasMore() {
+		return nil, ErrNoMorePages
+	}
+	if p.client == nil {
+		return nil, errors.New("framequery: page was not returned by ListJobs")
+	}
+	opts := p.opts
+	opts.Cursor = p.NextCursor
+	return p.client.ListJobs(ctx, &opts)
+}
+
+// ProcessOptions tunes polling behavior for Process, ProcessURL, and WaitForJob.
+// Defaults: 5s poll interval, 30s max adaptive interval, 30s per-poll request
+// timeout, 24h overall timeout.
+//
+// InitialDelayFromETA waits half the first reported ETA before the second
+// poll, so long jobs skip most of their early polls. The wait is capped by
+// MaxInitialDelay, which defaults to MaxPollInterval.
 type ProcessOptions struct {
-	PollInterval   time.Duration
-	Timeout        time.Duration
-	OnProgress     func(*Job)
-	CallbackURL    string
-	ProcessingMode string // "all", "transcript", "vision"
-	IdempotencyKey string
-	AudioTracks    []AudioTrack
+	PollInterval        time.Duration
+	MaxPollInterval     time.Duration
+	InitialDelayFromETA bool
+	MaxInitialDelay     time.Duration
+	Timeout             time.Duration
+	MaxPolls            int           // give up with ErrMaxPollsExceeded after this many non-terminal or hung polls; 0 means no limit
+	PollRequestTimeout  time.Duration // deadline for each GetJob; a stuck poll is retried
+	NotFoundGracePeriod time.Duration // how long 404s count as replication lag for new jobs (default 10s)
+	JustCreated         bool          // WaitForJob only: apply NotFoundGracePeriod; Process and ProcessURL always do
+	OnStart             func(*Job)    // called once, on the first status that isn't PENDING_UPLOAD, PENDING_FETCH, or another PENDING state
+	OnProgress          func(*Job)
+	OnProgressErr       func(*Job) error // non-nil error stops polling; the job keeps running server-side
+	CallbackURL         string
+	ProcessingMode      string // "all", "transcript", "vision"
+	IdempotencyKey      string
+	AudioTracks         []AudioTrack
+	Features            []Feature // nil runs all features
+	Language            string    // BCP-47 tag for speech recognition, e.g. "fr"; the API defaults to English
+	AutoDetect          bool      // let the API infer the spoken language
+	TranscriptDisabled  bool      // skip the transcript for faster, cheaper processing
+	EnableDiarization   bool      // label transcript segments by speaker; slower
+	SceneThreshold      float64   // scene cut sensitivity in (0, 1]; lower finds more scenes; 0 uses the API default
+	MaxScenes           int       // cap on scenes returned; 0 means no cap
+	IncludeSummary      bool      // ask for Scene.Summary and ProcessingResult.VideoSummary
+	SummaryMaxWords     int       // cap on each summary's length; 0 uses the API default
+	IncludeKeywords     bool      // ask for ProcessingResult.Keywords and Scene.Keywords
+	IncludeTopics       bool      // ask for ProcessingResult.Topics
+	CaptureRawResponse  bool      // keep the verbatim final response on ProcessingResult.RawBody
+	RawResponseWriter   io.Writer // streams the verbatim final response, fetched again once the job completes, instead of holding it
+	Metadata            map[string]string
+	Tags                []string
+	ExtraFields         map[string]any // see UploadOptions.ExtraFields
+	EventWriter         io.Writer      // receives NDJSON ProgressEvents; see EventSchemaVersion
+
+	// ReturnPartialOnTimeout makes a wait that runs out of time fetch the
+	// job once more and, if it has any processed data, return it as a
+	// result with Status StatusPartial alongside ErrPartialResult.
+	ReturnPartialOnTimeout bool
+
+	// SourceDuration and MaxSourceDuration are as in UploadOptions.
+	SourceDuration    time.Duration
+	MaxSourceDuration time.Duration
 }
 
 // UploadOptions overrides the filename derived from the file path.
@@ -139,13 +633,211 @@ type UploadOptions struct {
 	ProcessingMode string
 	IdempotencyKey string
 	AudioTracks    []AudioTrack
+	Features       []Feature
+	Language       string
+	AutoDetect     bool
+	Metadata       map[string]string
+	Tags           []string
+	EventWriter    io.Writer // receives job_created and upload_progress events
+
+	// ExtraFields are added to the job-creation request body as is, for API
+	// options the SDK doesn't have fields for yet. A key the SDK already set,
+	// such as fileName or one from a typed field, keeps the SDK's value.
+	ExtraFields map[string]any
+
+	TranscriptDisabled bool
+	EnableDiarization  bool
+	SceneThreshold     float64
+	MaxScenes          int
+	IncludeSummary     bool
+	SummaryMaxWords    int
+	IncludeKeywords    bool
+	IncludeTopics      bool
+
+	// When the API reports that the job accepts chunked uploads, files
+	// larger than ChunkSize (default 500MB) are uploaded in ChunkSize parts,
+	// each retried on its own. UseMultipart chunks any size without waiting
+	// for the API to say so. If the API has no part endpoint, the file goes
+	// in a single PUT. ChunkConcurrency parts upload at once (default 1,
+	// sequential).
+	ChunkSize        int64
+	UseMultipart     bool
+	ChunkConcurrency int
+
+	// Resumable retries a failed single-PUT upload from the last byte the
+	// storage holds, when it can tell, and checks the stored object's
+	// checksum afterwards. Job.UploadStats reports how it went.
+	Resumable bool
+
+	// SourceDuration is the video's length, if the caller knows it, and
+	// MaxSourceDuration the longest the plan accepts. When both are set and
+	// the source is longer, the call fails with a *SourceTooLongError
+	// instead of creating a job that would fail later.
+	SourceDuration    time.Duration
+	MaxSourceDuration time.Duration
+}
+
+// uploadOptions returns the job-creation subset of o. Nil-safe.
+func (o *ProcessOptions) uploadOptions() *UploadOptions {
+	if o == nil {
+		return nil
+	}
+	return &UploadOptions{
+		CallbackURL:    o.CallbackURL,
+		ProcessingMode: o.ProcessingMode,
+		IdempotencyKey: o.IdempotencyKey,
+		AudioTracks:    o.AudioTracks,
+		Features:       o.Features,
+		Language:       o.Language,
+		AutoDetect:     o.AutoDetect,
+		Metadata:       o.Metadata,
+		Tags:           o.Tags,
+		EventWriter:    o.EventWriter,
+		ExtraFields:    o.ExtraFields,
+
+		TranscriptDisabled: o.TranscriptDisabled,
+		EnableDiarization:  o.EnableDiarization,
+		SceneThreshold:     o.SceneThreshold,
+		MaxScenes:          o.MaxScenes,
+		IncludeSummary:     o.IncludeSummary,
+		SummaryMaxWords:    o.SummaryMaxWords,
+		IncludeKeywords:    o.IncludeKeywords,
+		IncludeTopics:      o.IncludeTopics,
+
+		SourceDuration:    o.SourceDuration,
+		MaxSourceDuration: o.MaxSourceDuration,
+	}
+}
+
+// UploadTarget is a signed location to send a job's video file to.
+// FieldName and FormFields apply to multipart POST uploads only.
+type UploadTarget struct {
+	URL        string
+	Method     string // "PUT" or "POST"
+	ExpiresAt  time.Time
+	FieldName  string
+	FormFields map[string]string
 }
 
+// Sort keys and orders for ListJobsOptions.
+const (
+	SortByCreatedAt = "createdAt"
+	SortByStatus    = "status"
+	SortByDuration  = "duration"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// MaxListJobsLimit is the largest page size ListJobs accepts.
+const MaxListJobsLimit = 100
+
 // ListJobsOptions filters and paginates ListJobs.
+// A Limit above MaxListJobsLimit is an error unless ClampLimit is set.
+//
+// Statuses matches any of several statuses, in addition to Status. The SDK
+// lists each status separately and merges the pages newest first by
+// CreatedAt, so a merged page may hold fewer than Limit jobs even when more
+// remain; NextCursor is then a combined cursor valid only for the same
+// statuses.
+//
+// CreatedAfter and CreatedBefore bound the job creation time; zero means no
+// bound. If the server ignores them, ListJobs filters each page itself.
+//
+// SortBy and SortOrder ask the server for an order other than its default,
+// newest first. A merged Statuses listing supports only the default.
 type ListJobsOptions struct {
-	Limit  int
-	Cursor string
-	Status string
+	Limit         int
+	Cursor        string
+	Status        JobStatus
+	Statuses      []JobStatus
+	Tag           string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	ClampLimit    bool
+
+	// FilenameContains matches a case-insensitive substring of Filename.
+	FilenameContains string
+
+	SortBy    string // a SortBy constant; empty for the server's default
+	SortOrder string // SortOrderAsc or SortOrderDesc; empty for the server's default
+
+	// MaxPages caps how many pages ForEachJobPage visits; 0 means no cap.
+	MaxPages int
+
+	// Strict reports malformed items (not an object, or a known field of the
+	// wrong type) in JobPage.DecodeErrors and leaves them out of Jobs.
+	// Without it, a job with a wrong-typed field is kept with that field
+	// zero, and only items that aren't objects are skipped. With
+	// WithStrictParsing, a malformed item fails the call instead.
+	Strict bool
+}
+
+// newestFirst reports whether the listing is in the default order, newest
+// first by CreatedAt. Nil-safe.
+func (o *ListJobsOptions) newestFirst() bool {
+	if o == nil || o.SortBy == "" {
+		return true
+	}
+	return o.SortBy == SortByCreatedAt && o.SortOrder != SortOrderAsc
+}
+
+// inRange reports whether j was created within the bounds. A job with an
+// unparseable CreatedAt is kept. passed is set when j is older than
+// CreatedAfter.
+func (o *ListJobsOptions) inRange(j *Job) (ok, passed bool) {
+	created, err := time.Parse(time.RFC3339, j.CreatedAt)
+	if err != nil {
+		return true, false
+	}
+	if !o.CreatedAfter.IsZero() && created.Before(o.CreatedAfter) {
+		return false, true
+	}
+	if !o.CreatedBefore.IsZero() && !created.Before(o.CreatedBefore) {
+		return false, false
+	}
+	return true, false
+}
+
+// JobPatch is the set of changes for UpdateJob.
+type JobPatch struct {
+	DisplayName string            `json:"displayName,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+// CloneOptions overrides settings for CloneJob. Empty fields keep the original job's values.
+type CloneOptions struct {
+	DisplayName    string
+	CallbackURL    string
+	ProcessingMode string
+	Features       []Feature
+	Metadata       map[string]string
+	Tags           []string
+}
+
+// DeleteJobsOptions selects jobs for DeleteJobs. Zero fields match everything.
+type DeleteJobsOptions struct {
+	Status        JobStatus
+	CreatedBefore time.Time
+	Tag           string
+	DryRun        bool
+}
+
+func (o *DeleteJobsOptions) matches(j *Job) bool {
+	if o.Status != "" && j.Status != o.Status {
+		return false
+	}
+	if !o.CreatedBefore.IsZero() {
+		created, err := time.Parse(time.RFC3339, j.CreatedAt)
+		if err != nil || !created.Before(o.CreatedBefore) {
+			return false
+		}
+	}
+	if o.Tag != "" && !containsString(j.Tags, o.Tag) {
+		return false
+	}
+	return true
 }
 
 // BatchClip is a single video clip in a batch request.
@@ -165,8 +857,8 @@ type BatchResult struct {
 
 // BatchJob is a single job entry in a BatchResult.
 type BatchJob struct {
-	JobID  string `json:"jobId"`
-	Status string `json:"status"`
+	JobID  string    `json:"jobId"`
+	Status JobStatus `json:"status"`
 }
 
 // BatchOptions configures CreateBatch and ProcessBatch.
@@ -178,6 +870,11 @@ type BatchOptions struct {
 	PollInterval   time.Duration
 	Timeout        time.Duration
 	OnProgress     func([]Job)
+
+	// ReserveHours, with a reservation ledger configured, makes ProcessBatch
+	// reserve that many hours before submitting and release them when it
+	// returns.
+	ReserveHours float64
 }
 
 // ---- Internal API response types ----
@@ -188,16 +885,24 @@ type apiEnvelope struct {
 }
 
 type createJobResponse struct {
-	JobID        string `json:"jobId"`
-	UploadURL    string `json:"uploadUrl"`
-	ExpiresIn    int    `json:"expiresInSeconds"`
-	UploadMethod string `json:"uploadMethod"`
-	Status       string `json:"status,omitempty"`
+	JobID        string    `json:"jobId"`
+	UploadURL    string    `json:"uploadUrl"`
+	ExpiresIn    int       `json:"expiresInSeconds"`
+	UploadMethod string    `json:"uploadMethod"`
+	Status       JobStatus `json:"status,omitempty"`
+
+	// Multipart POST uploads only
+	UploadFieldName string            `json:"uploadFieldName,omitempty"`
+	UploadFields    map[string]string `json:"uploadFields,omitempty"`
+
+	// MultipartUpload is set when the job accepts chunked uploads through
+	// upload-parts.
+	MultipartUpload bool `json:"multipartUpload,omitempty"`
 }
 
 type createJobFromURLResponse struct {
-	JobID  string `json:"jobId"`
-	Status string `json:"status"`
+	JobID  string    `json:"jobId"`
+	Status JobStatus `json:"status"`
 }
 
 type batchAPIResponse struct {
@@ -206,98 +911,334 @@ type batchAPIResponse struct {
 	Jobs    []BatchJob `json:"jobs"`
 }
 
-func parseJob(data map[string]any) *Job {
-	j := &Job{Raw: data}
-	if v, ok := data["jobId"].(string); ok {
-		j.ID = v
+// MarshalJSON encodes the result in the API's job shape: Raw overlaid with
+// the typed fields. UnmarshalJSON reverses it, so results can be cached and
+// restored without loss.
+func (r ProcessingResult) MarshalJSON() ([]byte, error) {
+	out := copyMap(r.Raw)
+	out["jobId"] = r.JobID
+	out["status"] = r.Status
+	out["originalFilename"] = r.Filename
+	out["createdAt"] = r.CreatedAt
+	if r.Features != nil {
+		out["features"] = r.Features
 	}
-	if v, ok := data["status"].(string); ok {
-		j.Status = v
+	if r.VideoMetadata != nil {
+		out["videoMetadata"] = r.VideoMetadata
 	}
-	if v, ok := data["originalFilename"].(string); ok {
-		j.Filename = v
+	pd, _ := out["processedData"].(map[string]any)
+	pd = copyMap(pd)
+	pd["length"] = r.Duration
+	pd["scenes"] = r.Scenes
+	pd["transcript"] = r.Transcript
+	if r.DetectedLanguage != "" {
+		pd["detectedLanguage"] = r.DetectedLanguage
 	}
-	if v, ok := data["createdAt"].(string); ok {
-		j.CreatedAt = v
+	if r.VideoSummary != "" {
+		pd["summary"] = r.VideoSummary
 	}
-	if v, ok := data["estimatedCompletionTimeSeconds"].(float64); ok {
-		j.ETASeconds = v
+	if r.Keywords != nil {
+		pd["keywords"] = r.Keywords
 	}
-	if v, ok := data["audioTrackCount"].(float64); ok {
-		n := int(v)
-		j.AudioTrackCount = &n
+	if r.Topics != nil {
+		pd["topics"] = r.Topics
 	}
-	if v, ok := data["audioTracksCompleted"].(float64); ok {
-		n := int(v)
-		j.AudioTracksCompleted = &n
+	out["processedData"] = pd
+	if r.Manifest != nil {
+		out[manifestKey] = r.Manifest
 	}
-	if names, ok := data["audioTrackNames"].([]any); ok {
-		for _, name := range names {
-			if s, ok := name.(string); ok {
-				j.AudioTrackNames = append(j.AudioTrackNames, s)
-			}
-		}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the shape produced by MarshalJSON (or a raw API job)
+// and populates both the typed fields and Raw.
+func (r *ProcessingResult) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	job, err := decodeJob(b)
+	if err != nil {
+		return err
+	}
+	*r = *job.result()
+	r.Manifest = decodeManifest(job.Raw)
+	return nil
+}
+
+// MarshalJSON encodes the job in the API's job shape: Raw overlaid with the
+// typed fields. Since that shape is covered by the API's own compatibility
+// guarantees, stored jobs stay readable by later SDK versions. RawBody and
+// UploadStats are not stored.
+func (j Job) MarshalJSON() ([]byte, error) {
+	out := copyMap(j.Raw)
+	out["jobId"] = j.ID
+	out["status"] = j.Status
+	out["originalFilename"] = j.Filename
+	out["createdAt"] = j.CreatedAt
+	if j.ETASeconds != 0 {
+		out["estimatedCompletionTimeSeconds"] = j.ETASeconds
+	}
+	if j.AudioTrackCount != nil {
+		out["audioTrackCount"] = *j.AudioTrackCount
+	}
+	if j.AudioTracksCompleted != nil {
+		out["audioTracksCompleted"] = *j.AudioTracksCompleted
+	}
+	if j.AudioTrackNames != nil {
+		out["audioTrackNames"] = j.AudioTrackNames
+	}
+	if j.DisplayName != "" {
+		out["displayName"] = j.DisplayName
+	}
+	if j.Metadata != nil {
+		out["metadata"] = j.Metadata
+	}
+	if j.Tags != nil {
+		out["tags"] = j.Tags
+	}
+	if j.ErrorMessage != "" {
+		out["errorMessage"] = j.ErrorMessage
+	}
+	if j.ErrorCode != "" {
+		out["errorCode"] = j.ErrorCode
+	}
+	if j.VideoMetadata != nil {
+		out["videoMetadata"] = j.VideoMetadata
+	}
+	if j.Manifest != nil {
+		out[manifestKey] = j.Manifest
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the shape produced by MarshalJSON (or a raw API job)
+// and populates both the typed fields and Raw.
+func (j *Job) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	job, err := decodeJob(b)
+	if err != nil {
+		return err
 	}
-	return j
+	*j = *job
+	j.Manifest = decodeManifest(job.Raw)
+	return nil
+}
+
+// copyMap returns a shallow copy of m; never nil.
+func copyMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// secondsToMillis converts API float seconds to integer milliseconds, rounding
+// half-up so values like 3599.9999999 land on 3600000 rather than drifting.
+// All time math and exporters should go through this so output is stable.
+func secondsToMillis(sec float64) int64 {
+	return int64(math.Floor(sec*1000 + 0.5))
 }
 
-func parseResult(data map[string]any) *ProcessingResult {
-	r := &ProcessingResult{Raw: data}
-	if v, ok := data["jobId"].(string); ok {
-		r.JobID = v
+// millisToSeconds converts integer milliseconds back to float seconds. It
+// inverts secondsToMillis exactly for any whole millisecond.
+func millisToSeconds(ms int64) float64 {
+	return float64(ms) / 1000
+}
+
+// secondsToDuration converts API float seconds to a Duration via
+// secondsToMillis.
+func secondsToDuration(sec float64) time.Duration {
+	return time.Duration(secondsToMillis(sec)) * time.Millisecond
+}
+
+// jobWire is the API's job object. A job is decoded into it once; the
+// result fields are kept for Job.result.
+type jobWire struct {
+	JobID                string           `json:"jobId"`
+	Status               JobStatus        `json:"status"`
+	OriginalFilename     string           `json:"originalFilename"`
+	CreatedAt            string           `json:"createdAt"`
+	ETASeconds           float64          `json:"estimatedCompletionTimeSeconds"`
+	AudioTrackCount      optionalInt      `json:"audioTrackCount"`
+	AudioTracksCompleted optionalInt      `json:"audioTracksCompleted"`
+	AudioTrackNames      lenientStrings   `json:"audioTrackNames"`
+	DisplayName          string           `json:"displayName"`
+	Metadata             lenientStringMap `json:"metadata"`
+	Tags                 lenientStrings   `json:"tags"`
+	Features             lenientStrings   `json:"features"`
+	DetectedLanguage     string           `json:"detectedLanguage"`
+	ErrorMessage         string           `json:"errorMessage"`
+	ErrorCode            string           `json:"errorCode"`
+	VideoMetadata        *VideoMetadata   `json:"videoMetadata"`
+	ProcessedData        *ProcessedData   `json:"processedData"`
+}
+
+// unmarshalLenient is json.Unmarshal, except that a value of the wrong type
+// leaves its field zero instead of failing, as the map-based parsing before
+// jobWire did. Only malformed JSON is an error.
+func unmarshalLenient(b []byte, v any) error {
+	err := json.Unmarshal(b, v)
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) {
+		return nil
 	}
-	if v, ok := data["status"].(string); ok {
-		r.Status = v
+	return err
+}
+
+// optionalInt decodes an integer that may be absent. It stays nil for null or
+// a value of the wrong type, where a plain *int would point at a zero.
+type optionalInt struct{ p *int }
+
+func (o *optionalInt) UnmarshalJSON(b []byte) error {
+	var n int
+	if string(b) != "null" && json.Unmarshal(b, &n) == nil {
+		o.p = &n
 	}
-	if v, ok := data["originalFilename"].(string); ok {
-		r.Filename = v
+	return nil
+}
+
+// lenientStrings decodes a JSON array, keeping only its string entries, as
+// the map-based parsing before jobWire did. A value that isn't an array
+// decodes to nil.
+type lenientStrings []string
+
+func (l *lenientStrings) UnmarshalJSON(b []byte) error {
+	var items []json.RawMessage
+	if json.Unmarshal(b, &items) != nil || items == nil {
+		*l = nil
+		return nil
 	}
-	if v, ok := data["createdAt"].(string); ok {
-		r.CreatedAt = v
+	out := make(lenientStrings, 0, len(items))
+	for _, it := range items {
+		var s string
+		if string(it) != "null" && json.Unmarshal(it, &s) == nil {
+			out = append(out, s)
+		}
 	}
+	*l = out
+	return nil
+}
 
-	if pd, ok := data["processedData"].(map[string]any); ok {
-		if v, ok := pd["length"].(float64); ok {
-			r.Duration = v
+// lenientStringMap decodes a JSON object, keeping only its string values. A
+// value that isn't an object decodes to nil.
+type lenientStringMap map[string]string
+
+func (m *lenientStringMap) UnmarshalJSON(b []byte) error {
+	var items map[string]json.RawMessage
+	if json.Unmarshal(b, &items) != nil || items == nil {
+		*m = nil
+		return nil
+	}
+	out := make(lenientStringMap, len(items))
+	for k, it := range items {
+		var s string
+		if string(it) != "null" && json.Unmarshal(it, &s) == nil {
+			out[k] = s
 		}
-		if scenes, ok := pd["scenes"].([]any); ok {
-			for _, s := range scenes {
-				if sm, ok := s.(map[string]any); ok {
-					scene := Scene{}
-					if v, ok := sm["description"].(string); ok {
-						scene.Description = v
-					}
-					if v, ok := sm["endTs"].(float64); ok {
-						scene.EndTime = v
-					}
-					if objs, ok := sm["objects"].([]any); ok {
-						for _, o := range objs {
-							if str, ok := o.(string); ok {
-								scene.Objects = append(scene.Objects, str)
-							}
-						}
-					}
-					r.Scenes = append(r.Scenes, scene)
-				}
+	}
+	*m = out
+	return nil
+}
+
+// features returns w.Features as Features; nil if the job had none.
+func (w *jobWire) features() []Feature {
+	if w.Features == nil {
+		return nil
+	}
+	fs := make([]Feature, len(w.Features))
+	for i, f := range w.Features {
+		fs[i] = Feature(f)
+	}
+	return fs
+}
+
+// decodeJob decodes one API job object into both the typed fields and Raw.
+// A known field of the wrong type is left zero, and entries that aren't
+// strings are dropped from the string lists and metadata; checkJobTypes
+// reports both. Only a value that isn't an object is an error.
+func decodeJob(b []byte) (*Job, error) {
+	return decodeJobRaw(b, true)
+}
+
+// decodeJobRaw is decodeJob, leaving Raw nil unless keepRaw is set.
+func decodeJobRaw(b []byte, keepRaw bool) (*Job, error) {
+	var raw map[string]any
+	if keepRaw {
+		if err := json.Unmarshal(b, &raw); err != nil {
+			var te *json.UnmarshalTypeError
+			if errors.As(err, &te) {
+				return nil, fmt.Errorf("job is a JSON %s, not an object", te.Value)
 			}
+			return nil, err
 		}
-		if transcript, ok := pd["transcript"].([]any); ok {
-			for _, t := range transcript {
-				if tm, ok := t.(map[string]any); ok {
-					seg := TranscriptSegment{}
-					if v, ok := tm["StartTime"].(float64); ok {
-						seg.StartTime = v
-					}
-					if v, ok := tm["EndTime"].(float64); ok {
-						seg.EndTime = v
-					}
-					if v, ok := tm["Text"].(string); ok {
-						seg.Text = v
-					}
-					r.Transcript = append(r.Transcript, seg)
-				}
-			}
+		if raw == nil {
+			return nil, errors.New("job is null")
+		}
+	} else if t := bytes.TrimSpace(b); len(t) == 0 || t[0] != '{' {
+		if string(t) == "null" {
+			return nil, errors.New("job is null")
+		}
+		return nil, fmt.Errorf("job is not a JSON object: %.20s", t)
+	}
+	w := &jobWire{}
+	if err := unmarshalLenient(b, w); err != nil {
+		return nil, err
+	}
+	return &Job{
+		ID:                   w.JobID,
+		Status:               w.Status,
+		Filename:             w.OriginalFilename,
+		CreatedAt:            w.CreatedAt,
+		ETASeconds:           w.ETASeconds,
+		AudioTrackCount:      w.AudioTrackCount.p,
+		AudioTracksCompleted: w.AudioTracksCompleted.p,
+		AudioTrackNames:      []string(w.AudioTrackNames),
+		DisplayName:          w.DisplayName,
+		Metadata:             map[string]string(w.Metadata),
+		Tags:                 []string(w.Tags),
+		ErrorMessage:         w.ErrorMessage,
+		ErrorCode:            w.ErrorCode,
+		VideoMetadata:        w.VideoMetadata,
+		Raw:                  raw,
+		wire:                 w,
+	}, nil
+}
+
+// result returns the job's result fields. A Job not built by decodeJob has
+// its Raw decoded now, leaving fields that don't decode zero.
+func (j *Job) result() *ProcessingResult {
+	w := j.wire
+	if w == nil {
+		w = &jobWire{}
+		if b, err := json.Marshal(j.Raw); err == nil {
+			_ = json.Unmarshal(b, w)
+		}
+	}
+	r := &ProcessingResult{
+		JobID:            w.JobID,
+		Status:           w.Status,
+		Filename:         w.OriginalFilename,
+		CreatedAt:        w.CreatedAt,
+		Features:         w.features(),
+		DetectedLanguage: w.DetectedLanguage,
+		VideoMetadata:    w.VideoMetadata,
+		Raw:              j.Raw,
+	}
+	if pd := w.ProcessedData; pd != nil {
+		// Copied so results from the same job can be edited independently
+		r.Duration = pd.Length
+		r.Scenes = append([]Scene(nil), pd.Scenes...)
+		deriveSceneStarts(r.Scenes)
+		r.Transcript = append([]TranscriptSegment(nil), pd.Transcript...)
+		if pd.DetectedLanguage != "" {
+			r.DetectedLanguage = pd.DetectedLanguage
 		}
+		r.VideoSummary = pd.Summary
+		r.Keywords = append([]string(nil), pd.Keywords...)
+		r.Topics = append([]string(nil), pd.Topics...)
 	}
 	return r
 }
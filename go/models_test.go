@@ -0,0 +1,449 @@
+package framequery
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscriptSegmentCasing(t *testing.T) {
+	want := TranscriptSegment{StartTime: 1.5, EndTime: 3, Text: "hello", Speaker: "A"}
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"pascal", `{"StartTime":1.5,"EndTime":3,"Text":"hello","Speaker":"A"}`},
+		{"camel", `{"startTime":1.5,"endTime":3,"text":"hello","speaker":"A"}`},
+		{"ts", `{"startTs":1.5,"endTs":3,"text":"hello","speaker":"A"}`},
+		{"mixed, canonical wins", `{"startTs":9,"StartTime":1.5,"endTime":3,"EndTime":3,"text":"bye","Text":"hello","Speaker":"A"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TranscriptSegment
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+
+			// Typed decode and the Raw fallback for jobs not built by decodeJob
+			body := `{"jobId":"j","status":"VISION_COMPLETED","processedData":{"transcript":[` + tt.json + `]}}`
+			job, err := decodeJob([]byte(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			fromRaw := &Job{Raw: job.Raw}
+			for _, r := range []*ProcessingResult{job.result(), fromRaw.result()} {
+				if len(r.Transcript) != 1 || r.Transcript[0] != want {
+					t.Errorf("result transcript = %+v, want [%+v]", r.Transcript, want)
+				}
+			}
+			if err := checkJobShape([]byte(body)); err != nil {
+				t.Errorf("checkJobShape: %v", err)
+			}
+		})
+	}
+
+	var seg TranscriptSegment
+	if err := json.Unmarshal([]byte(`{"startTime":"1.5","text":"hi"}`), &seg); err != nil || seg != (TranscriptSegment{Text: "hi"}) {
+		t.Errorf("string startTime: %+v, %v; want it left zero", seg, err)
+	}
+}
+
+func TestSummaryFields(t *testing.T) {
+	body := `{"jobId":"j","status":"VISION_COMPLETED","processedData":{"summary":"A cooking show.","scenes":[{"description":"kitchen","summary":"Chef intro","endTs":4}]}}`
+	job, err := decodeJob([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := job.result()
+	if r.VideoSummary != "A cooking show." || len(r.Scenes) != 1 || r.Scenes[0].Summary != "Chef intro" {
+		t.Fatalf("summaries = %q, %+v", r.VideoSummary, r.Scenes)
+	}
+	if err := checkJobShape([]byte(body)); err != nil {
+		t.Errorf("checkJobShape: %v", err)
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back ProcessingResult
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.VideoSummary != r.VideoSummary || back.Scenes[0].Summary != "Chef intro" {
+		t.Errorf("round trip lost summaries: %q, %+v", back.VideoSummary, back.Scenes)
+	}
+	if r.Fingerprint() == (&ProcessingResult{Scenes: r.Scenes}).Fingerprint() {
+		t.Error("fingerprint ignores VideoSummary")
+	}
+}
+
+func TestDurationAccessors(t *testing.T) {
+	tests := []struct {
+		sec  float64
+		want time.Duration
+	}{
+		{0, 0},
+		{1.5, 1500 * time.Millisecond},
+		{3599.9999999, time.Hour},
+		{0.0004, 0},
+		{0.0005, time.Millisecond},
+		{12.3456, 12346 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		seg := TranscriptSegment{StartTime: tt.sec, EndTime: tt.sec}
+		r := ProcessingResult{Duration: tt.sec}
+		j := Job{ETASeconds: tt.sec}
+		got := []time.Duration{seg.StartD(), seg.EndD(), Scene{EndTime: tt.sec}.EndD(), r.DurationD(), j.ETAD(), j.ETADuration()}
+		for i, d := range got {
+			if d != tt.want {
+				t.Errorf("%g s: accessor %d = %v, want %v", tt.sec, i, d, tt.want)
+			}
+		}
+	}
+}
+
+const fullJobFixture = `{
+	"jobId": "job_1",
+	"status": "VISION_COMPLETED",
+	"originalFilename": "talk.mp4",
+	"createdAt": "2026-03-01T10:00:00Z",
+	"estimatedCompletionTimeSeconds": 42.5,
+	"audioTrackCount": 2,
+	"audioTracksCompleted": 1,
+	"audioTrackNames": ["en", "fr"],
+	"displayName": "Keynote",
+	"metadata": {"team": "ingest"},
+	"tags": ["conf"],
+	"features": ["scenes", "transcript"],
+	"detectedLanguage": "en",
+	"errorMessage": "partial",
+	"errorCode": "E1",
+	"unknownField": {"nested": [1, 2]},
+	"processedData": {
+		"length": 120.25,
+		"detectedLanguage": "en",
+		"summary": "A talk.",
+		"scenes": [
+			{"description": "stage", "summary": "intro", "endTs": 10, "keyFrameUrl": "https://x/1.jpg", "objects": ["person"]},
+			{"description": "slides", "endTs": 20, "objects": [{"label": "screen", "confidence": 0.9}],
+			 "objectTracks": [{"label": "screen", "confidence": 0.9, "spans": [{"start": 11, "end": 19}]}]}
+		],
+		"transcript": [{"StartTime": 0, "EndTime": 4.5, "Text": "Welcome", "Speaker": "A"}],
+		"extraData": true
+	}
+}`
+
+func TestJobJSONRoundTrip(t *testing.T) {
+	var job Job
+	if err := json.Unmarshal([]byte(fullJobFixture), &job); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Job
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	job.wire, back.wire = nil, nil
+	if !reflect.DeepEqual(job, back) {
+		t.Errorf("round trip changed the job:\n got %+v\nwant %+v", back, job)
+	}
+	if back.ETASeconds != 42.5 || back.CreatedAt != "2026-03-01T10:00:00Z" || back.ErrorCode != "E1" {
+		t.Errorf("typed fields lost: %+v", back)
+	}
+	if _, ok := back.Raw["unknownField"]; !ok {
+		t.Error("Raw lost unknownField")
+	}
+}
+
+func TestProcessingResultJSONRoundTrip(t *testing.T) {
+	for _, fixture := range []string{fullJobFixture, `{"jobId":"j","status":"VIDEO_COMPLETED_NO_SCENES","processedData":{"scenes":[],"transcript":[]}}`} {
+		var r ProcessingResult
+		if err := json.Unmarshal([]byte(fixture), &r); err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var back ProcessingResult
+		if err := json.Unmarshal(b, &back); err != nil {
+			t.Fatal(err)
+		}
+		// The first encoding normalizes Raw; after that it is a fixed point
+		again, err := json.Marshal(back)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(b) {
+			t.Errorf("second encoding differs:\n got %s\nwant %s", again, b)
+		}
+		if _, ok := back.Raw["unknownField"]; fixture == fullJobFixture && !ok {
+			t.Error("Raw lost unknownField")
+		}
+		r.Raw, back.Raw = nil, nil
+		if !reflect.DeepEqual(r, back) {
+			t.Errorf("round trip changed the result:\n got %+v\nwant %+v", back, r)
+		}
+	}
+}
+
+// BenchmarkDecodeJobRaw compares bytes allocated decoding a long job with and
+// without Raw; run with -benchmem.
+func BenchmarkDecodeJobRaw(b *testing.B) {
+	var segs []string
+	for i := 0; i < 5000; i++ {
+		segs = append(segs, fmt.Sprintf(`{"StartTime":%d,"EndTime":%d,"Text":"segment number %d of a long talk"}`, i, i+1, i))
+	}
+	body := []byte(`{"jobId":"j","status":"VISION_COMPLETED","processedData":{"length":5000,"transcript":[` + strings.Join(segs, ",") + `]}}`)
+	for _, keep := range []bool{true, false} {
+		b.Run(fmt.Sprintf("keepRaw=%v", keep), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := decodeJobRaw(body, keep); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSceneStartTimes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scenes string
+		starts []float64
+		ends   []float64
+	}{
+		{"in order", `[{"endTs":4},{"endTs":10},{"endTs":12.5}]`, []float64{0, 4, 10}, []float64{4, 10, 12.5}},
+		{"unsorted", `[{"endTs":10},{"endTs":12.5},{"endTs":4}]`, []float64{0, 4, 10}, []float64{4, 10, 12.5}},
+		{"explicit startTs", `[{"startTs":1,"endTs":4},{"startTs":5,"endTs":10},{"endTs":12}]`, []float64{1, 5, 10}, []float64{4, 10, 12}},
+		{"empty", `[]`, []float64{}, []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := decodeJob([]byte(`{"jobId":"j","status":"VISION_COMPLETED","processedData":{"scenes":` + tt.scenes + `}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := job.result()
+			starts, ends := []float64{}, []float64{}
+			for _, s := range r.Scenes {
+				starts = append(starts, s.StartTime)
+				ends = append(ends, s.EndTime)
+				if s.Duration() != s.EndTime-s.StartTime || s.DurationD() != s.EndD()-s.StartD() {
+					t.Errorf("scene %+v: Duration %v, DurationD %v", s, s.Duration(), s.DurationD())
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.starts) || !reflect.DeepEqual(ends, tt.ends) {
+				t.Errorf("starts %v ends %v, want %v %v", starts, ends, tt.starts, tt.ends)
+			}
+		})
+	}
+}
+
+func TestKeywordsTopics(t *testing.T) {
+	body := `{"jobId":"j","status":"VISION_COMPLETED","processedData":{"keywords":["pasta","kitchen"],"topics":["cooking"],"scenes":[{"endTs":4,"keywords":["knife"]}]}}`
+	job, err := decodeJob([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := job.result()
+	if !reflect.DeepEqual(r.Keywords, []string{"pasta", "kitchen"}) || !reflect.DeepEqual(r.Topics, []string{"cooking"}) ||
+		!reflect.DeepEqual(r.Scenes[0].Keywords, []string{"knife"}) {
+		t.Fatalf("keywords %v topics %v scene %v", r.Keywords, r.Topics, r.Scenes[0].Keywords)
+	}
+	if err := checkJobShape([]byte(body)); err != nil {
+		t.Errorf("checkJobShape: %v", err)
+	}
+	b, _ := json.Marshal(r)
+	var back ProcessingResult
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Keywords, r.Keywords) || !reflect.DeepEqual(back.Topics, r.Topics) {
+		t.Errorf("round trip: %v %v", back.Keywords, back.Topics)
+	}
+	without := *r
+	without.Topics = nil
+	if r.Fingerprint() == without.Fingerprint() {
+		t.Error("fingerprint ignores Topics")
+	}
+}
+
+func TestJobETA(t *testing.T) {
+	if eta := (&Job{}).ETA(); !eta.IsZero() {
+		t.Errorf("no estimate: ETA = %v, want zero", eta)
+	}
+	before := time.Now()
+	eta := (&Job{ETASeconds: 90.5}).ETA()
+	if lo, hi := before.Add(90500*time.Millisecond), time.Now().Add(90500*time.Millisecond); eta.Before(lo) || eta.After(hi) {
+		t.Errorf("ETA = %v, want within [%v, %v]", eta, lo, hi)
+	}
+}
+
+func TestVideoMetadata(t *testing.T) {
+	const withMeta = `{
+		"jobId": "job_2",
+		"status": "VISION_COMPLETED",
+		"originalFilename": "match.mov",
+		"createdAt": "2026-05-02T08:30:00Z",
+		"videoMetadata": {"width": 1920, "height": 1080, "fps": "30000/1001", "codec": "h264", "bitrate": 8000000, "audioChannels": 2},
+		"processedData": {"length": 90, "scenes": [{"description": "pitch", "endTs": 90}], "transcript": []}
+	}`
+	tests := []struct {
+		name string
+		body string
+		want *VideoMetadata
+	}{
+		{"present", withMeta, &VideoMetadata{Width: 1920, Height: 1080, FPS: 30000.0 / 1001, Codec: "h264", Bitrate: 8000000, AudioChannels: 2}},
+		{"absent", `{"jobId":"job_2","status":"VISION_COMPLETED","originalFilename":"match.mov","processedData":{"length":90,"scenes":[],"transcript":[]}}`, nil},
+		{"partial", `{"jobId":"j","status":"INGEST_COMPLETED","videoMetadata":{"width":640,"fps":25}}`, &VideoMetadata{Width: 640, FPS: 25}},
+		{"null fps", `{"jobId":"j","status":"QUEUED","videoMetadata":{"codec":"vp9","fps":null}}`, &VideoMetadata{Codec: "vp9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := decodeJob([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := job.result()
+			if !reflect.DeepEqual(job.VideoMetadata, tt.want) || !reflect.DeepEqual(r.VideoMetadata, tt.want) {
+				t.Errorf("job %+v, result %+v; want %+v", job.VideoMetadata, r.VideoMetadata, tt.want)
+			}
+			if err := checkJobShape([]byte(tt.body)); err != nil {
+				t.Errorf("checkJobShape: %v", err)
+			}
+			b, err := json.Marshal(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var back ProcessingResult
+			if err := json.Unmarshal(b, &back); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(back.VideoMetadata, tt.want) {
+				t.Errorf("round trip: %+v", back.VideoMetadata)
+			}
+		})
+	}
+
+	body := `{"jobId":"j","videoMetadata":{"width":1280,"fps":"fast"}}`
+	if job, err := decodeJob([]byte(body)); err != nil || *job.VideoMetadata != (VideoMetadata{Width: 1280}) {
+		t.Errorf(`fps "fast": %+v, %v; want it left zero`, job.VideoMetadata, err)
+	}
+	if checkJobTypes([]byte(body)) == nil {
+		t.Error(`fps "fast": checkJobTypes passed it`)
+	}
+}
+
+func TestFrameAtTime(t *testing.T) {
+	r := &ProcessingResult{VideoMetadata: &VideoMetadata{FPS: 30}}
+	for _, tt := range []struct {
+		t    float64
+		want int
+	}{{0, 0}, {1.0 / 30, 1}, {0.5, 15}, {1.999, 59}, {2, 60}, {-1, -1}} {
+		if got := r.FrameAtTime(tt.t); got != tt.want {
+			t.Errorf("FrameAtTime(%g) = %d, want %d", tt.t, got, tt.want)
+		}
+	}
+	if got := (&ProcessingResult{}).FrameAtTime(1); got != -1 {
+		t.Errorf("no metadata: FrameAtTime = %d, want -1", got)
+	}
+}
+
+// wrongTypesJob has a known field of the wrong type at each level.
+const wrongTypesJob = `{"jobId":7,"status":"QUEUED","originalFilename":"a.mp4","estimatedCompletionTimeSeconds":"soon","audioTrackCount":"2","displayName":3,
+	"videoMetadata":{"width":"wide","height":720},
+	"processedData":{"length":"12","scenes":[{"description":1,"endTs":4}],"transcript":[{"StartTime":"1.5","EndTime":2,"Text":"hi"}]}}`
+
+func TestDecodeJobTolerance(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		check func(t *testing.T, j *Job, r *ProcessingResult)
+	}{
+		{"missing fields", `{"jobId":"j"}`, func(t *testing.T, j *Job, r *ProcessingResult) {
+			if j.ID != "j" || j.Status != "" || j.Metadata != nil || j.Tags != nil || r.Scenes != nil || r.Features != nil {
+				t.Errorf("job %+v, result %+v", j, r)
+			}
+		}},
+		{"nulls", `{"jobId":"j","status":null,"metadata":null,"tags":null,"features":null,"audioTrackCount":null,"processedData":{"scenes":null,"transcript":null}}`,
+			func(t *testing.T, j *Job, r *ProcessingResult) {
+				if j.Status != "" || j.Metadata != nil || j.Tags != nil || j.AudioTrackCount != nil || r.Scenes != nil || r.Transcript != nil {
+					t.Errorf("job %+v, result %+v", j, r)
+				}
+			}},
+		{"extra keys", `{"jobId":"j","status":"QUEUED","future":{"a":1},"processedData":{"newThing":[1],"scenes":[{"endTs":3,"mood":"calm"}]}}`,
+			func(t *testing.T, j *Job, r *ProcessingResult) {
+				if j.Status != StatusQueued || len(r.Scenes) != 1 || r.Scenes[0].EndTime != 3 {
+					t.Errorf("job %+v, result %+v", j, r)
+				}
+				if _, ok := j.Raw["future"]; j.Raw != nil && !ok {
+					t.Error("Raw lost the extra key")
+				}
+			}},
+		{"non-string metadata and tags", `{"jobId":"j","metadata":{"team":"ingest","priority":3,"flags":{"x":true},"owner":null},"tags":["a",1,null,"b"],"audioTrackNames":["en",{}],"features":["scenes",7]}`,
+			func(t *testing.T, j *Job, r *ProcessingResult) {
+				if !reflect.DeepEqual(j.Metadata, map[string]string{"team": "ingest"}) {
+					t.Errorf("Metadata = %v", j.Metadata)
+				}
+				if !reflect.DeepEqual(j.Tags, []string{"a", "b"}) || !reflect.DeepEqual(j.AudioTrackNames, []string{"en"}) {
+					t.Errorf("Tags = %v, AudioTrackNames = %v", j.Tags, j.AudioTrackNames)
+				}
+				if !reflect.DeepEqual(r.Features, []Feature{FeatureScenes}) {
+					t.Errorf("Features = %v", r.Features)
+				}
+			}},
+		{"wrong container types", `{"jobId":"j","metadata":"none","tags":"a,b"}`, func(t *testing.T, j *Job, r *ProcessingResult) {
+			if j.Metadata != nil || j.Tags != nil {
+				t.Errorf("Metadata = %v, Tags = %v", j.Metadata, j.Tags)
+			}
+		}},
+		{"wrong scalar types", wrongTypesJob, func(t *testing.T, j *Job, r *ProcessingResult) {
+			if j.ID != "" || j.Status != StatusQueued || j.ETASeconds != 0 || j.AudioTrackCount != nil || j.DisplayName != "" || j.Filename != "a.mp4" {
+				t.Errorf("job = %+v", j)
+			}
+			if *j.VideoMetadata != (VideoMetadata{Height: 720}) {
+				t.Errorf("VideoMetadata = %+v", j.VideoMetadata)
+			}
+			if r.Duration != 0 || len(r.Scenes) != 1 || r.Scenes[0].Description != "" || r.Scenes[0].EndTime != 4 {
+				t.Errorf("Duration = %v, Scenes = %+v", r.Duration, r.Scenes)
+			}
+			if want := (TranscriptSegment{EndTime: 2, Text: "hi"}); len(r.Transcript) != 1 || r.Transcript[0] != want {
+				t.Errorf("Transcript = %+v", r.Transcript)
+			}
+		}},
+		{"non-string objects", `{"jobId":"j","processedData":{"scenes":[{"endTs":2,"objects":["person",42,true,null,["x"],{"label":"dog","confidence":0.8},{"label":5}]}]}}`,
+			func(t *testing.T, j *Job, r *ProcessingResult) {
+				s := r.Scenes[0]
+				if !reflect.DeepEqual(s.Objects, []string{"person", "dog"}) || len(s.Detections) != 1 || s.Detections[0].Confidence != 0.8 {
+					t.Errorf("Objects = %v, Detections = %+v", s.Objects, s.Detections)
+				}
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, keep := range []bool{true, false} {
+				j, err := decodeJobRaw([]byte(tt.body), keep)
+				if err != nil {
+					t.Fatalf("keepRaw %v: %v", keep, err)
+				}
+				tt.check(t, j, j.result())
+			}
+		})
+	}
+
+	if _, err := decodeJob([]byte(`["j"]`)); err == nil {
+		t.Error("array job: want an error")
+	}
+}
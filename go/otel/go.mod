@@ -0,0 +1,11 @@
+module github.com/framequery/framequery-go/otel
+
+go 1.22
+
+require (
+	github.com/framequery/framequery-go v0.1.0
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+replace github.com/framequery/framequery-go => ../
@@ -0,0 +1,78 @@
+// Package otel adds OpenTelemetry tracing to the FrameQuery client. It lives in
+// its own module so the core SDK stays free of OpenTelemetry dependencies.
+//
+//	client := framequery.New("fq_...", fqotel.WithOTelTracing(otel.GetTracerProvider()))
+package otel
+
+import (
+	"net/http"
+	"strings"
+
+	framequery "github.com/framequery/framequery-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/framequery/framequery-go/otel"
+
+// WithOTelTracing starts a client span for every HTTP request the client
+// makes, as a child of the span in the request context, and propagates the
+// trace via W3C Trace Context headers.
+func WithOTelTracing(tp trace.TracerProvider) framequery.Option {
+	tracer := tp.Tracer(tracerName)
+	prop := propagation.TraceContext{}
+	return framequery.WithMiddleware(framequery.InterceptorFunc(func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		attrs := []attribute.KeyValue{
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", redactedURL(req)),
+		}
+		if id := jobID(req.URL.Path); id != "" {
+			attrs = append(attrs, attribute.String("framequery.job_id", id))
+		}
+		ctx, span := tracer.Start(req.Context(), "framequery "+req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		req = req.Clone(ctx)
+		prop.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		return resp, nil
+	}))
+}
+
+// redactedURL drops the query string, which carries signatures on upload URLs.
+func redactedURL(req *http.Request) string {
+	u := *req.URL
+	u.RawQuery = ""
+	return u.String()
+}
+
+// jobID extracts the ID from paths like /v1/api/jobs/{id}/...
+func jobID(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if p == "jobs" && i+1 < len(parts) {
+			switch id := parts[i+1]; id {
+			case "", "batch", "from-url":
+				return ""
+			default:
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// PipelineRetryPolicy controls ProcessWithRetry.
+type PipelineRetryPolicy struct {
+	MaxAttempts int           // default 3
+	Deadline    time.Time     // attempts run until this and none starts after it; zero means none
+	Backoff     time.Duration // pause between attempts; default grows from 500ms
+}
+
+// PipelineAttempt records one ProcessWithRetry attempt. JobID is empty if
+// the attempt failed before a job was created. CleanupErr is set if
+// cancelling the abandoned job failed.
+type PipelineAttempt struct {
+	JobID      string
+	Started    time.Time
+	Elapsed    time.Duration
+	Err        error
+	CleanupErr error
+}
+
+// PipelineError is returned by ProcessWithRetry when every attempt failed.
+// It unwraps to the last attempt's error.
+type PipelineError struct {
+	Attempts []PipelineAttempt
+}
+
+func (e *PipelineError) Error() string {
+	last := e.Attempts[len(e.Attempts)-1]
+	return fmt.Sprintf("framequery: process failed after %d attempts (last: %v)", len(e.Attempts), last.Err)
+}
+
+// Unwrap returns the last attempt's error.
+func (e *PipelineError) Unwrap() error {
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// ProcessWithRetry runs Process and, if any stage fails, starts over with a
+// fresh job, up to policy.MaxAttempts times and only while policy.Deadline
+// has not passed. Each attempt runs under the deadline, so one stuck past it
+// is cut short. A failed attempt's job is cancelled if it is still running.
+// Auth and permission errors, ErrSourceTooLong, and ctx cancellation are not
+// retried.
+//
+// With opts.ReturnPartialOnTimeout, an attempt cut short at the deadline
+// hands back what it has: the partial result is returned alongside the
+// *PipelineError.
+//
+// Each attempt is a distinct job on purpose: a set opts.IdempotencyKey gets an
+// "-attempt-N" suffix from the second attempt on, so the API doesn't hand back
+// the failed job.
+func (c *Client) ProcessWithRetry(ctx context.Context, path string, opts *ProcessOptions, policy PipelineRetryPolicy) (*ProcessingResult, error) {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	var attempts []PipelineAttempt
+	var partial *ProcessingResult
+	for n := 1; n <= maxAttempts; n++ {
+		if n > 1 {
+			wait := policy.Backoff
+			if wait <= 0 {
+				wait = c.backoff(n - 2)
+			}
+			if !policy.Deadline.IsZero() && time.Now().Add(wait).After(policy.Deadline) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, &PipelineError{Attempts: attempts}
+			case <-time.After(wait):
+			}
+		}
+
+		attemptOpts := ProcessOptions{}
+		if opts != nil {
+			attemptOpts = *opts
+		}
+		if n > 1 && attemptOpts.IdempotencyKey != "" {
+			attemptOpts.IdempotencyKey += "-attempt-" + strconv.Itoa(n)
+		}
+
+		a := PipelineAttempt{Started: time.Now()}
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if !policy.Deadline.IsZero() {
+			attemptCtx, cancel = context.WithDeadline(ctx, policy.Deadline)
+		}
+		result, job, err := c.processAttempt(attemptCtx, path, &attemptOpts)
+		cancel()
+		a.Elapsed = time.Since(a.Started)
+		if err == nil {
+			return result, nil
+		}
+		a.Err = err
+		partial = result
+		if job != nil {
+			a.JobID = job.ID
+			a.CleanupErr = c.abandonJob(ctx, job.ID)
+		}
+		attempts = append(attempts, a)
+
+		if ctx.Err() != nil || IsAuthError(err) || IsPermissionError(err) || errors.Is(err, ErrSourceTooLong) {
+			break
+		}
+		if !policy.Deadline.IsZero() && !time.Now().Before(policy.Deadline) {
+			break
+		}
+	}
+	return partial, &PipelineError{Attempts: attempts}
+}
+
+// processAttempt is Process, also returning the job once it exists.
+func (c *Client) processAttempt(ctx context.Context, path string, opts *ProcessOptions) (*ProcessingResult, *Job, error) {
+	job, err := c.upload(ctx, path, opts.uploadOptions())
+	if err != nil {
+		return nil, job, err
+	}
+	result, err := c.waitForUpload(ctx, job, opts)
+	return result, job, err
+}
+
+// abandonJob cancels a job left behind by a failed attempt, unless it has
+// already finished. It runs even if ctx is done.
+func (c *Client) abandonJob(ctx context.Context, jobID string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultPollReqTimeout)
+	defer cancel()
+	job, err := c.GetJob(ctx, jobID)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	if job.IsTerminal() {
+		return nil
+	}
+	_, err = c.CancelJob(ctx, jobID)
+	if IsConflictError(err) {
+		// Finished in the meantime
+		return nil
+	}
+	return err
+}
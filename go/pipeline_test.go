@@ -0,0 +1,129 @@
+package framequery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pipelineServer fakes the API for ProcessWithRetry: each POST /jobs creates
+// job N, reported with statuses[N-1] from then on (the last entry if fewer).
+type pipelineServer struct {
+	*httptest.Server
+	statuses []string // job JSON after "status": for each attempt
+
+	mu        sync.Mutex
+	keys      []string // idempotency key of each created job
+	cancelled []string
+}
+
+func newPipelineServer(t *testing.T, statuses ...string) *pipelineServer {
+	s := &pipelineServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs":
+			var body struct {
+				IdempotencyKey string `json:"idempotencyKey"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			s.keys = append(s.keys, body.IdempotencyKey)
+			fmt.Fprintf(w, `{"data":{"jobId":"j%d","uploadUrl":%q}}`, len(s.keys), s.URL+"/put")
+		case r.Method == http.MethodPut && r.URL.Path == "/put":
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/jobs/j"):
+			var n int
+			fmt.Sscanf(r.URL.Path, "/jobs/j%d", &n)
+			st := s.statuses[min(n, len(s.statuses))-1]
+			fmt.Fprintf(w, `{"data":{"jobId":"j%d","status":%s}}`, n, st)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/cancel"):
+			s.cancelled = append(s.cancelled, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/jobs/"), "/cancel"))
+			w.Write([]byte(`{"data":{}}`))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+func writePipelineFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte("video"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestProcessWithRetryFreshJobs(t *testing.T) {
+	srv := newPipelineServer(t,
+		`"FAILED","errorMessage":"transcode"`,
+		`"VISION_COMPLETED","processedData":{"length":5}`)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+
+	r, err := c.ProcessWithRetry(context.Background(), writePipelineFile(t),
+		&ProcessOptions{PollInterval: 5 * time.Millisecond, IdempotencyKey: "nightly"},
+		PipelineRetryPolicy{Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.JobID != "j2" || r.Duration != 5 {
+		t.Errorf("result = %+v", r)
+	}
+	if want := []string{"nightly", "nightly-attempt-2"}; strings.Join(srv.keys, ",") != strings.Join(want, ",") {
+		t.Errorf("idempotency keys = %q, want %q", srv.keys, want)
+	}
+	if len(srv.cancelled) > 0 {
+		t.Errorf("cancelled %v; the failed job had already finished", srv.cancelled)
+	}
+}
+
+func TestProcessWithRetryDeadline(t *testing.T) {
+	srv := newPipelineServer(t, `"VIDEO_PROCESSING"`)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+
+	start := time.Now()
+	_, err := c.ProcessWithRetry(context.Background(), writePipelineFile(t),
+		&ProcessOptions{PollInterval: 5 * time.Millisecond}, // default 24h Timeout
+		PipelineRetryPolicy{Deadline: start.Add(100 * time.Millisecond), Backoff: time.Millisecond})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("returned after %v; the attempt was not cut at the deadline", elapsed)
+	}
+	var pe *PipelineError
+	if !errors.As(err, &pe) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a *PipelineError wrapping DeadlineExceeded", err)
+	}
+	if len(pe.Attempts) != 1 || pe.Attempts[0].JobID != "j1" {
+		t.Errorf("attempts = %+v, want one, for j1", pe.Attempts)
+	}
+	if len(srv.cancelled) != 1 || srv.cancelled[0] != "j1" {
+		t.Errorf("cancelled %v, want [j1]", srv.cancelled)
+	}
+}
+
+func TestProcessWithRetryDeadlinePartial(t *testing.T) {
+	srv := newPipelineServer(t, `"VISION_PROCESSING","processedData":{"length":8,"scenes":[{"description":"intro","endTs":4}]}`)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+
+	r, err := c.ProcessWithRetry(context.Background(), writePipelineFile(t),
+		&ProcessOptions{PollInterval: 5 * time.Millisecond, ReturnPartialOnTimeout: true},
+		PipelineRetryPolicy{Deadline: time.Now().Add(50 * time.Millisecond)})
+	if !errors.Is(err, ErrPartialResult) {
+		t.Fatalf("err = %v, want ErrPartialResult", err)
+	}
+	if r == nil || r.Status != StatusPartial || len(r.Scenes) != 1 {
+		t.Errorf("result = %+v, want the partial scenes", r)
+	}
+}
@@ -0,0 +1,77 @@
+package framequery
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitInfo is the rate limit state reported by the most recent API response.
+// A header the response didn't carry leaves its field unknown: HasLimit or
+// HasRemaining is false, or ResetAt is zero.
+type RateLimitInfo struct {
+	Limit        int
+	Remaining    int
+	ResetAt      time.Time
+	HasLimit     bool
+	HasRemaining bool
+}
+
+// IsExhausted reports whether the response said no requests remain. It is
+// false when Remaining wasn't reported.
+func (r *RateLimitInfo) IsExhausted() bool {
+	return r.HasRemaining && r.Remaining <= 0
+}
+
+// rateTracker holds the latest RateLimitInfo for a Client.
+type rateTracker struct {
+	mu   sync.Mutex
+	last *RateLimitInfo
+}
+
+// LastRateLimitInfo returns the rate limit headers from the most recent API
+// response, or nil if none have been seen.
+func (c *Client) LastRateLimitInfo() *RateLimitInfo {
+	c.rates.mu.Lock()
+	defer c.rates.mu.Unlock()
+	if c.rates.last == nil {
+		return nil
+	}
+	info := *c.rates.last
+	return &info
+}
+
+func (c *Client) recordRateLimit(h http.Header) {
+	info, ok := parseRateLimit(h)
+	if !ok {
+		return
+	}
+	c.rates.mu.Lock()
+	c.rates.last = info
+	c.rates.mu.Unlock()
+}
+
+// parseRateLimit reads X-RateLimit-Limit, -Remaining, and -Reset. Reset may be
+// a Unix timestamp or a number of seconds from now.
+func parseRateLimit(h http.Header) (*RateLimitInfo, bool) {
+	info := &RateLimitInfo{}
+	if n, err := strconv.Atoi(h.Get("X-RateLimit-Limit")); err == nil {
+		info.Limit, info.HasLimit = n, true
+	}
+	if n, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil {
+		info.Remaining, info.HasRemaining = n, true
+	}
+	if !info.HasLimit && !info.HasRemaining {
+		return nil, false
+	}
+	if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		// Anything before 2001 is a delta, not an epoch
+		if reset < 1e9 {
+			info.ResetAt = time.Now().Add(time.Duration(reset) * time.Second)
+		} else {
+			info.ResetAt = time.Unix(reset, 0)
+		}
+	}
+	return info, true
+}
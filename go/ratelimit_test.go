@@ -0,0 +1,89 @@
+package framequery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		headers   map[string]string
+		want      *RateLimitInfo
+		exhausted bool
+	}{
+		{"none", nil, nil, false},
+		{"unparseable", map[string]string{"X-RateLimit-Limit": "lots"}, nil, false},
+		{"both", map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0"},
+			&RateLimitInfo{Limit: 60, HasLimit: true, Remaining: 0, HasRemaining: true}, true},
+		{"limit only", map[string]string{"X-RateLimit-Limit": "60"},
+			&RateLimitInfo{Limit: 60, HasLimit: true}, false},
+		{"remaining only", map[string]string{"X-RateLimit-Remaining": "7"},
+			&RateLimitInfo{Remaining: 7, HasRemaining: true}, false},
+		{"epoch reset", map[string]string{"X-RateLimit-Remaining": "7", "X-RateLimit-Reset": "1767225600"},
+			&RateLimitInfo{Remaining: 7, HasRemaining: true, ResetAt: time.Unix(1767225600, 0)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			got, ok := parseRateLimit(h)
+			if ok != (tt.want != nil) {
+				t.Fatalf("ok = %v, want %v", ok, tt.want != nil)
+			}
+			if !ok {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.IsExhausted() != tt.exhausted {
+				t.Errorf("IsExhausted = %v, want %v", got.IsExhausted(), tt.exhausted)
+			}
+		})
+	}
+
+	h := http.Header{"X-Ratelimit-Remaining": {"1"}, "X-Ratelimit-Reset": {"30"}}
+	got, _ := parseRateLimit(h)
+	if d := time.Until(got.ResetAt); d < 29*time.Second || d > 30*time.Second {
+		t.Errorf("delta reset is %v away, want 30s", d)
+	}
+}
+
+func TestLastRateLimitInfo(t *testing.T) {
+	remaining := []string{"5", "", "4"}
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v := remaining[calls]; v != "" {
+			w.Header().Set("X-RateLimit-Remaining", v)
+		}
+		calls++
+		w.Write([]byte(`{"data":{"status":"ok"}}`))
+	}))
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(0))
+	ctx := context.Background()
+
+	if info := c.LastRateLimitInfo(); info != nil {
+		t.Errorf("before any request: %+v, want nil", info)
+	}
+	want := []int{5, 5, 4} // a response without the headers keeps the last info
+	for i, w := range want {
+		if err := c.Ping(ctx); err != nil {
+			t.Fatal(err)
+		}
+		info := c.LastRateLimitInfo()
+		if info == nil || info.Remaining != w || !info.HasRemaining || info.HasLimit {
+			t.Fatalf("after request %d: %+v, want Remaining %d and Limit unknown", i+1, info, w)
+		}
+		info.Remaining = 0 // a copy; doesn't touch the client's
+	}
+	if info := c.LastRateLimitInfo(); info.Remaining != 4 {
+		t.Errorf("Remaining = %d after modifying a returned copy, want 4", info.Remaining)
+	}
+}
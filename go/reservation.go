@@ -0,0 +1,103 @@
+package framequery
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Reservation is a claim on processing hours held in a Ledger.
+type Reservation struct {
+	ID        string
+	Hours     float64
+	ExpiresAt time.Time
+}
+
+// Ledger tracks outstanding reservations for clients sharing one account. The
+// API has no reservation endpoint, so reservations are cooperative: they only
+// hold back clients that use the same Ledger. Back it with shared storage
+// (e.g. Redis) to coordinate across processes.
+type Ledger interface {
+	// Reserve records r if the hours of all unexpired reservations plus
+	// r.Hours fit within available; otherwise it returns ErrInsufficientQuota.
+	// The check and the write must be atomic.
+	Reserve(ctx context.Context, r Reservation, available float64) error
+
+	// Release removes a reservation. Releasing an unknown ID is not an error.
+	Release(ctx context.Context, id string) error
+}
+
+// WithReservationLedger sets the Ledger used by ReserveCredits and
+// ProcessBatch.
+func WithReservationLedger(l Ledger) Option {
+	return func(c *Client) {
+		c.ledger = l
+	}
+}
+
+// ReserveCredits claims hours of the account's quota for ttl. It fails with
+// ErrInsufficientQuota if the hours are already spoken for. Requires
+// WithReservationLedger.
+func (c *Client) ReserveCredits(ctx context.Context, hours float64, ttl time.Duration) (*Reservation, error) {
+	if c.ledger == nil {
+		return nil, errors.New("framequery: no reservation ledger configured")
+	}
+	q, err := c.GetQuota(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var id [8]byte
+	rand.Read(id[:])
+	r := Reservation{ID: hex.EncodeToString(id[:]), Hours: hours, ExpiresAt: time.Now().Add(ttl)}
+	if err := c.ledger.Reserve(ctx, r, q.TotalAvailableHours()); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// ReleaseReservation returns a reservation's hours to the pool.
+func (c *Client) ReleaseReservation(ctx context.Context, r *Reservation) error {
+	if c.ledger == nil {
+		return errors.New("framequery: no reservation ledger configured")
+	}
+	return c.ledger.Release(ctx, r.ID)
+}
+
+// NewMemoryLedger returns a Ledger for clients within one process.
+func NewMemoryLedger() Ledger {
+	return &memoryLedger{reservations: map[string]Reservation{}}
+}
+
+type memoryLedger struct {
+	mu           sync.Mutex
+	reservations map[string]Reservation
+}
+
+func (l *memoryLedger) Reserve(_ context.Context, r Reservation, available float64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	held := 0.0
+	for id, existing := range l.reservations {
+		if !existing.ExpiresAt.After(now) {
+			delete(l.reservations, id)
+			continue
+		}
+		held += existing.Hours
+	}
+	if held+r.Hours > available {
+		return ErrInsufficientQuota
+	}
+	l.reservations[r.ID] = r
+	return nil
+}
+
+func (l *memoryLedger) Release(_ context.Context, id string) error {
+	l.mu.Lock()
+	delete(l.reservations, id)
+	l.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,55 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReserveCredits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quota" {
+			t.Errorf("unexpected %s %s; a refused reservation must not create jobs", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"data":{"includedHours":3,"creditsBalanceHours":2}}`))
+	}))
+	defer srv.Close()
+	ledger := NewMemoryLedger()
+	a := New("k", WithBaseURL(srv.URL), WithReservationLedger(ledger))
+	b := New("k", WithBaseURL(srv.URL), WithReservationLedger(ledger))
+	ctx := context.Background()
+
+	held, err := a.ReserveCredits(ctx, 3, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if held.Hours != 3 || held.ID == "" || time.Until(held.ExpiresAt) < 59*time.Minute {
+		t.Errorf("reservation = %+v", held)
+	}
+	if _, err := b.ReserveCredits(ctx, 3, time.Hour); !errors.Is(err, ErrInsufficientQuota) {
+		t.Errorf("over-reserving from another client: err = %v, want ErrInsufficientQuota", err)
+	}
+	if _, err := b.ProcessBatch(ctx, &BatchOptions{ReserveHours: 3}); !errors.Is(err, ErrInsufficientQuota) {
+		t.Errorf("ProcessBatch over the quota: err = %v, want ErrInsufficientQuota", err)
+	}
+	if _, err := b.ReserveCredits(ctx, 2, time.Millisecond); err != nil {
+		t.Errorf("reserving the remaining 2h: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond) // the 2h reservation expires
+
+	if err := a.ReleaseReservation(ctx, held); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReserveCredits(ctx, 5, time.Hour); err != nil {
+		t.Errorf("reserving everything after the release and expiry: %v", err)
+	}
+
+	if _, err := New("k", WithBaseURL(srv.URL)).ReserveCredits(ctx, 1, time.Hour); err == nil {
+		t.Error("ReserveCredits without a ledger succeeded")
+	}
+}
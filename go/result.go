@@ -0,0 +1,274 @@
+package framequery
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FullTranscript returns every segment's text joined with spaces.
+func (r *ProcessingResult) FullTranscript() string {
+	parts := make([]string, 0, len(r.Transcript))
+	for _, t := range r.Transcript {
+		if t.Text != "" {
+			parts = append(parts, t.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// TranscriptText returns the transcript as plain text. Each segment is
+// trimmed, its internal whitespace collapsed, and segments are joined with
+// single spaces. A segment whose text ends in a newline ends a paragraph,
+// giving a blank line; a segment of only whitespace with a newline
+// does the same for the one before it.
+func (r *ProcessingResult) TranscriptText() string {
+	var b strings.Builder
+	r.eachTranscriptText(func(_ TranscriptSegment, text string, para bool) {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+		if para {
+			b.WriteString("\n\n")
+		}
+	})
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// TranscriptTextWithTimestamps returns the transcript one segment per line,
+// each prefixed with its start time and a space, and a blank line at
+// paragraph ends as in TranscriptText. format is a time.Format layout for
+// the offset from the start of the video, such as "[04:05]" or
+// "15:04:05.000"; empty means "[04:05]", or "[15:04:05]" if the transcript
+// runs an hour or more.
+func (r *ProcessingResult) TranscriptTextWithTimestamps(format string) string {
+	if format == "" {
+		format = "[04:05]"
+		for _, t := range r.Transcript {
+			if t.EndTime >= 3600 || t.StartTime >= 3600 {
+				format = "[15:04:05]"
+				break
+			}
+		}
+	}
+	var lines []string
+	r.eachTranscriptText(func(t TranscriptSegment, text string, para bool) {
+		stamp := time.Time{}.Add(t.StartD()).Format(format)
+		lines = append(lines, stamp+" "+text)
+		if para {
+			lines = append(lines, "")
+		}
+	})
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// Words returns the number of whitespace-separated words in the transcript.
+func (r *ProcessingResult) Words() int {
+	n := 0
+	for _, t := range r.Transcript {
+		n += len(strings.Fields(t.Text))
+	}
+	return n
+}
+
+// eachTranscriptText calls fn for each segment with text, in transcript
+// order, with its normalized text and whether a paragraph ends after it.
+func (r *ProcessingResult) eachTranscriptText(fn func(t TranscriptSegment, text string, para bool)) {
+	var prev *TranscriptSegment
+	var prevText string
+	var prevPara bool
+	for i, t := range r.Transcript {
+		para := strings.HasSuffix(strings.TrimRight(t.Text, " \t\r"), "\n")
+		text := strings.Join(strings.Fields(t.Text), " ")
+		if text == "" {
+			prevPara = prevPara || para
+			continue
+		}
+		if prev != nil {
+			fn(*prev, prevText, prevPara)
+		}
+		prev, prevText, prevPara = &r.Transcript[i], text, para
+	}
+	if prev != nil {
+		fn(*prev, prevText, prevPara)
+	}
+}
+
+// FrameAtTime returns the index of the frame showing at t seconds, counting
+// from 0, using VideoMetadata.FPS. It returns -1 when the frame rate is
+// unknown or t is negative.
+func (r *ProcessingResult) FrameAtTime(t float64) int {
+	if r.VideoMetadata == nil || r.VideoMetadata.FPS <= 0 || t < 0 {
+		return -1
+	}
+	// The epsilon keeps a frame boundary like 1/30*30 from rounding down
+	return int(math.Floor(t*r.VideoMetadata.FPS + 1e-9))
+}
+
+// TranscriptInRange returns segments overlapping [start, end] in seconds.
+// Partial overlap counts.
+func (r *ProcessingResult) TranscriptInRange(start, end float64) []TranscriptSegment {
+	var out []TranscriptSegment
+	for _, t := range r.Transcript {
+		if t.StartTime <= end && t.EndTime >= start {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SearchTranscript returns the segments whose Text contains query, in
+// transcript order. Without caseSensitive, case is folded per Unicode.
+func (r *ProcessingResult) SearchTranscript(query string, caseSensitive bool) []TranscriptSegment {
+	if !caseSensitive {
+		query = strings.ToLower(query)
+	}
+	var out []TranscriptSegment
+	for _, t := range r.Transcript {
+		text := t.Text
+		if !caseSensitive {
+			text = strings.ToLower(text)
+		}
+		if strings.Contains(text, query) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SearchTranscriptRegex returns the segments whose Text matches pattern, in
+// transcript order.
+func (r *ProcessingResult) SearchTranscriptRegex(pattern *regexp.Regexp) []TranscriptSegment {
+	var out []TranscriptSegment
+	for _, t := range r.Transcript {
+		if pattern.MatchString(t.Text) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// SpeakerTimeline groups transcript segments by Speaker, each group in
+// transcript order. Segments without a speaker are under "".
+func (r *ProcessingResult) SpeakerTimeline() map[string][]TranscriptSegment {
+	out := make(map[string][]TranscriptSegment)
+	for _, t := range r.Transcript {
+		out[t.Speaker] = append(out[t.Speaker], t)
+	}
+	return out
+}
+
+// ScenesInRange returns scenes overlapping [start, end] in seconds, in time
+// order. A scene spans from its StartTime, or the previous scene's EndTime if
+// it has none, to its EndTime.
+func (r *ProcessingResult) ScenesInRange(start, end float64) []Scene {
+	var out []Scene
+	for _, s := range sceneSpans(r.Scenes) {
+		if s.StartTime <= end && s.EndTime >= start {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ObjectTimeline returns when an object label appears, in time order. A scene
+// with an ObjectTrack for the label contributes the tracked spans; other
+// scenes containing the label contribute their whole range.
+func (r *ProcessingResult) ObjectTimeline(label string) []TimeRange {
+	var out []TimeRange
+	for _, s := range sceneSpans(r.Scenes) {
+		tracked := false
+		for _, t := range s.ObjectTracks {
+			if t.Label == label {
+				out = append(out, t.Spans...)
+				tracked = true
+			}
+		}
+		if !tracked && containsString(s.ObjectLabels(), label) {
+			out = append(out, TimeRange{Start: s.StartTime, End: s.EndTime})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start < out[j].Start })
+	return out
+}
+
+// Shift returns a copy of r with every scene, transcript, and object-track
+// time moved by offset, as when the clip starts offset into a longer video.
+// The arithmetic is on whole milliseconds, so Shift(-offset) restores the
+// times exactly. Duration, the clip's length, is unchanged.
+func (r *ProcessingResult) Shift(offset time.Duration) *ProcessingResult {
+	d := offset.Round(time.Millisecond).Milliseconds()
+	return r.mapTimes(func(ms int64) int64 { return ms + d }, false)
+}
+
+// Scale returns a copy of r with every time, Duration included, multiplied
+// by factor, as for a video sped up or slowed down by that much. Each time
+// is rounded half-up to the millisecond, so Scale(f) then Scale(1/f) comes
+// back within 1ms for f >= 1; scaling down first can lose times closer than
+// a millisecond apart.
+func (r *ProcessingResult) Scale(factor float64) *ProcessingResult {
+	return r.mapTimes(func(ms int64) int64 { return int64(math.Floor(float64(ms)*factor + 0.5)) }, true)
+}
+
+// mapTimes returns a copy of r with f applied to each time in milliseconds,
+// and to Duration if withDuration is set. Raw and RawBody keep the API's
+// original times. Scenes get explicit start times
+// first, so a derived start moves with its scene.
+func (r *ProcessingResult) mapTimes(f func(ms int64) int64, withDuration bool) *ProcessingResult {
+	conv := func(sec float64) float64 { return millisToSeconds(f(secondsToMillis(sec))) }
+	out := &ProcessingResult{
+		JobID:            r.JobID,
+		Status:           r.Status,
+		Filename:         r.Filename,
+		Duration:         r.Duration,
+		Scenes:           sceneSpans(r.Scenes),
+		Transcript:       append([]TranscriptSegment(nil), r.Transcript...),
+		Features:         r.Features,
+		CreatedAt:        r.CreatedAt,
+		DetectedLanguage: r.DetectedLanguage,
+		VideoSummary:     r.VideoSummary,
+		Keywords:         r.Keywords,
+		Topics:           r.Topics,
+		VideoMetadata:    r.VideoMetadata,
+		Raw:              r.Raw,
+		RawBody:          r.RawBody,
+		RawBodySHA256:    r.RawBodySHA256,
+		Err:              r.Err,
+		Manifest:         r.Manifest,
+	}
+	if withDuration {
+		out.Duration = conv(r.Duration)
+	}
+	for i := range out.Scenes {
+		s := &out.Scenes[i]
+		s.StartTime, s.EndTime = conv(s.StartTime), conv(s.EndTime)
+		s.ObjectTracks = cloneTracks(s.ObjectTracks)
+		for j := range s.ObjectTracks {
+			for k := range s.ObjectTracks[j].Spans {
+				sp := &s.ObjectTracks[j].Spans[k]
+				sp.Start, sp.End = conv(sp.Start), conv(sp.End)
+			}
+		}
+	}
+	for i := range out.Transcript {
+		t := &out.Transcript[i]
+		t.StartTime, t.EndTime = conv(t.StartTime), conv(t.EndTime)
+	}
+	return out
+}
+
+// cloneTracks copies tracks and their spans.
+func cloneTracks(tracks []ObjectTrack) []ObjectTrack {
+	if tracks == nil {
+		return nil
+	}
+	out := make([]ObjectTrack, len(tracks))
+	for i, t := range tracks {
+		out[i] = t
+		out[i].Spans = append([]TimeRange(nil), t.Spans...)
+	}
+	return out
+}
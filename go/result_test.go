@@ -0,0 +1,203 @@
+package framequery
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// messyTranscript has the whitespace real transcripts arrive with: padding,
+// tabs, doubled spaces, blank segments, and paragraph-ending newlines.
+var messyTranscript = &ProcessingResult{Transcript: []TranscriptSegment{
+	{StartTime: 0, EndTime: 2, Text: "  Good morning,\teveryone. "},
+	{StartTime: 2, EndTime: 4, Text: "Thanks   for coming.\n"},
+	{StartTime: 4, EndTime: 5, Text: "   "},
+	{StartTime: 5, EndTime: 8, Text: "\nFirst, the\nagenda."},
+	{StartTime: 8, EndTime: 9, Text: ""},
+	{StartTime: 83.6, EndTime: 90, Text: "Any questions? \n  "},
+	{StartTime: 90, EndTime: 91, Text: "\n"},
+	{StartTime: 91, EndTime: 93, Text: "Right — let's go."},
+}}
+
+func TestTranscriptText(t *testing.T) {
+	want := "Good morning, everyone. Thanks for coming.\n\n" +
+		"First, the agenda. Any questions?\n\n" +
+		"Right — let's go."
+	if got := messyTranscript.TranscriptText(); got != want {
+		t.Errorf("TranscriptText:\n got %q\nwant %q", got, want)
+	}
+	if got := (&ProcessingResult{}).TranscriptText(); got != "" {
+		t.Errorf("empty transcript: %q", got)
+	}
+	if got := messyTranscript.Words(); got != 15 {
+		t.Errorf("Words = %d, want 15", got)
+	}
+}
+
+func TestTranscriptTextWithTimestamps(t *testing.T) {
+	tests := []struct {
+		name, format string
+		r            *ProcessingResult
+		want         string
+	}{
+		{"default", "", messyTranscript, "[00:00] Good morning, everyone.\n" +
+			"[00:02] Thanks for coming.\n\n" +
+			"[00:05] First, the agenda.\n" +
+			"[01:23] Any questions?\n\n" +
+			"[01:31] Right — let's go."},
+		{"custom layout", "15:04:05.000 -", &ProcessingResult{Transcript: []TranscriptSegment{{StartTime: 83.6, Text: "hi"}}}, "00:01:23.600 - hi"},
+		{"hour long", "", &ProcessingResult{Transcript: []TranscriptSegment{{StartTime: 5, Text: "start"}, {StartTime: 3725, EndTime: 3730, Text: "end"}}},
+			"[00:00:05] start\n[01:02:05] end"},
+		{"empty", "", &ProcessingResult{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.TranscriptTextWithTimestamps(tt.format); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// randomResult builds a result whose times carry sub-millisecond noise and
+// land near rounding boundaries, as float seconds from the API do.
+func randomResult(rng *rand.Rand) *ProcessingResult {
+	sec := func() float64 {
+		v := float64(rng.IntN(7_200_000)) / 1000
+		switch rng.IntN(3) {
+		case 0:
+			v += 0.0005 - 1e-9
+		case 1:
+			v += rng.Float64() / 1000
+		}
+		return v
+	}
+	r := &ProcessingResult{Duration: 7200}
+	for range 1 + rng.IntN(8) {
+		a, b := sec(), sec()
+		r.Transcript = append(r.Transcript, TranscriptSegment{StartTime: min(a, b), EndTime: max(a, b), Text: "line"})
+	}
+	for range 1 + rng.IntN(4) {
+		a, b := sec(), sec()
+		r.Scenes = append(r.Scenes, Scene{
+			StartTime:    min(a, b),
+			EndTime:      max(a, b),
+			Description:  "scene",
+			ObjectTracks: []ObjectTrack{{Label: "person", Spans: []TimeRange{{Start: min(a, b), End: max(a, b)}}}},
+		})
+	}
+	return r
+}
+
+// resultMillis lists every time in r as rounded milliseconds.
+func resultMillis(r *ProcessingResult) []int64 {
+	var out []int64
+	for _, s := range sceneSpans(r.Scenes) {
+		out = append(out, secondsToMillis(s.StartTime), secondsToMillis(s.EndTime))
+		for _, tr := range s.ObjectTracks {
+			for _, sp := range tr.Spans {
+				out = append(out, secondsToMillis(sp.Start), secondsToMillis(sp.End))
+			}
+		}
+	}
+	for _, seg := range r.Transcript {
+		out = append(out, secondsToMillis(seg.StartTime), secondsToMillis(seg.EndTime))
+	}
+	return out
+}
+
+// exports renders every subtitle format of r.
+func exports(t *testing.T, r *ProcessingResult) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := r.WriteSRT(&b); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteVTT(&b); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteChaptersVTT(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestShiftRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(268, 2))
+	for i := range 500 {
+		r := randomResult(rng)
+		before := resultMillis(r)
+		offset := time.Duration(rng.Int64N(int64(2*time.Hour))) - time.Hour
+		back := r.Shift(offset).Shift(-offset)
+		if got := resultMillis(back); !reflect.DeepEqual(got, before) {
+			t.Fatalf("case %d, offset %v: round trip = %v, want %v", i, offset, got, before)
+		}
+		if got := resultMillis(r); !reflect.DeepEqual(got, before) {
+			t.Fatalf("case %d: Shift modified its receiver", i)
+		}
+		if got, want := exports(t, back), exports(t, r); got != want {
+			t.Fatalf("case %d: exports differ after round trip:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+func TestShiftMovesEveryTime(t *testing.T) {
+	r := &ProcessingResult{
+		Duration:   10,
+		Scenes:     []Scene{{EndTime: 4, ObjectTracks: []ObjectTrack{{Label: "dog", Spans: []TimeRange{{Start: 1, End: 2}}}}}},
+		Transcript: []TranscriptSegment{{StartTime: 0.5, EndTime: 3.9995, Text: "hi"}},
+	}
+	got := r.Shift(90 * time.Second)
+	if got.Duration != 10 {
+		t.Errorf("Duration = %v, want 10", got.Duration)
+	}
+	if s := got.Scenes[0]; s.StartTime != 90 || s.EndTime != 94 {
+		t.Errorf("scene = %v-%v, want 90-94", s.StartTime, s.EndTime)
+	}
+	if sp := got.Scenes[0].ObjectTracks[0].Spans[0]; sp != (TimeRange{Start: 91, End: 92}) {
+		t.Errorf("span = %+v", sp)
+	}
+	if seg := got.Transcript[0]; seg.StartTime != 90.5 || seg.EndTime != 94 {
+		t.Errorf("segment = %v-%v, want 90.5-94", seg.StartTime, seg.EndTime)
+	}
+	if r.Scenes[0].ObjectTracks[0].Spans[0].Start != 1 {
+		t.Error("Shift modified the receiver's track spans")
+	}
+}
+
+func TestScaleRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(268, 3))
+	for i := range 500 {
+		r := randomResult(rng)
+		f := 1 + 3*rng.Float64()
+		before := resultMillis(r)
+		back := resultMillis(r.Scale(f).Scale(1 / f))
+		for j, ms := range before {
+			if d := back[j] - ms; d < -1 || d > 1 {
+				t.Fatalf("case %d, factor %v: time %d came back as %dms, want %dms ±1", i, f, j, back[j], ms)
+			}
+		}
+	}
+}
+
+func TestExportsByteIdentical(t *testing.T) {
+	rng := rand.New(rand.NewPCG(268, 4))
+	for i := range 100 {
+		r := randomResult(rng)
+		if a, b := exports(t, r), exports(t, r.Shift(0)); a != b {
+			t.Fatalf("case %d: exports differ between runs:\n%s\nand:\n%s", i, a, b)
+		}
+	}
+	// 3599.9999999s rounds to the hour rather than printing 00:59:59,1000.
+	r := &ProcessingResult{Transcript: []TranscriptSegment{{StartTime: 3599.9999999, EndTime: 3601, Text: "hour"}}}
+	srt, err := r.TranscriptSRT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(srt, "01:00:00,000 --> 01:00:01,000") {
+		t.Errorf("SRT = %q", srt)
+	}
+}
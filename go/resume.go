@@ -0,0 +1,215 @@
+package framequery
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UploadStats describes how a resumable upload went. It is set on the Job
+// returned by Upload when UploadOptions.Resumable is on.
+type UploadStats struct {
+	Attempts  int     // PUT requests sent, including resumes
+	BytesSent int64   // bytes transmitted, including any sent more than once
+	ResumedAt []int64 // offset each resumed attempt started from
+	// RangeUnsupported is set when the storage URL didn't answer ranged
+	// reads, so retries re-sent the whole file.
+	RangeUnsupported bool
+	// Verified is set when the storage reported a checksum of the stored
+	// object (an MD5 ETag or x-goog-hash) and it matched the file.
+	Verified bool
+}
+
+// errChecksumMismatch means the stored object doesn't match the file.
+var errChecksumMismatch = errors.New("stored object checksum does not match the file")
+
+// putResumable PUTs src to uploadURL, retrying up to maxRetries times. After
+// a failed attempt it asks the URL how many bytes it holds (see
+// probeUpload) and PUTs only the rest. If the URL can't say, later attempts
+// start over.
+func (c *Client) putResumable(ctx context.Context, uploadURL string, src io.ReaderAt, size int64, jobID string, events *eventWriter) (*UploadStats, error) {
+	stats := &UploadStats{}
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		var off int64
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			case <-time.After(c.backoff(attempt - 1)):
+			}
+			if !stats.RangeUnsupported {
+				n, h, err := c.probeUpload(ctx, uploadURL, size)
+				switch {
+				case err != nil:
+					lastErr = err
+					continue
+				case h != nil:
+					// Already complete; trust it only if the checksum agrees
+					if ok, err := verifyUpload(h, src, size); ok && err == nil {
+						stats.Verified = true
+						return stats, nil
+					}
+					stats.RangeUnsupported = true
+				case n < 0:
+					stats.RangeUnsupported = true
+				default:
+					off = n
+					stats.ResumedAt = append(stats.ResumedAt, off)
+				}
+			}
+		}
+
+		stats.Attempts++
+		h, sent, err := c.putFrom(ctx, uploadURL, src, off, size, jobID, events)
+		stats.BytesSent += sent
+		if err == nil {
+			stats.Verified, err = verifyUpload(h, src, size)
+			if err != nil {
+				return stats, fmt.Errorf("framequery: upload: %w", err)
+			}
+			return stats, nil
+		}
+		lastErr = err
+		var se *uploadStatusError
+		if errors.As(err, &se) && se.code < 500 && se.code != http.StatusTooManyRequests && se.code != http.StatusRequestTimeout {
+			break
+		}
+	}
+	return stats, fmt.Errorf("framequery: upload: %w", lastErr)
+}
+
+// uploadStatusError is a non-2xx response to an upload PUT.
+type uploadStatusError struct {
+	code int
+	msg  string
+}
+
+func (e *uploadStatusError) Error() string { return e.msg }
+
+// putFrom sends src from off to the end. A resumed attempt carries a
+// Content-Range header. It returns the response headers and the bytes sent.
+func (c *Client) putFrom(ctx context.Context, uploadURL string, src io.ReaderAt, off, size int64, jobID string, events *eventWriter) (http.Header, int64, error) {
+	var body io.Reader = io.NewSectionReader(src, off, size-off)
+	counter := &countingReader{r: body}
+	body = counter
+	if events != nil {
+		body = &progressReader{r: body, events: events, jobID: jobID, total: size, sent: off, lastPct: off * 100 / size}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.ContentLength = size - off
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if off > 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", off, size-1, size))
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, counter.n, err
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, counter.n, &uploadStatusError{code: resp.StatusCode, msg: fmt.Sprintf("failed %s: %s", resp.Status, string(b))}
+	}
+	return resp.Header, counter.n, nil
+}
+
+// probeUpload asks how much of the upload the storage URL holds, with a
+// one-byte ranged GET that leaves the object untouched: a 206 reply's
+// Content-Range gives the stored size. It returns the offset to resume
+// from, or -1 if the URL doesn't answer ranged reads. If the object is
+// already complete, it returns the reply's headers for checking.
+func (c *Client) probeUpload(ctx context.Context, uploadURL string, size int64) (int64, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uploadURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1))
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// "Content-Range: bytes 0-0/N" means N bytes stored
+		_, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/")
+		stored, err := strconv.ParseInt(total, 10, 64)
+		switch {
+		case !ok || err != nil || stored > size:
+			return -1, nil, nil
+		case stored == size:
+			return size, resp.Header, nil
+		}
+		return stored, nil, nil
+	case http.StatusNotFound, http.StatusRequestedRangeNotSatisfiable:
+		// Nothing stored yet, or an empty object
+		return 0, nil, nil
+	default:
+		// A 200 ignored the range; anything else refused the read
+		return -1, nil, nil
+	}
+}
+
+// verifyUpload compares the checksum the storage reported for the stored
+// object with src's MD5. It reports false, with no error, when the storage
+// reported none.
+func verifyUpload(h http.Header, src io.ReaderAt, size int64) (bool, error) {
+	want := storedMD5(h)
+	if want == nil {
+		return false, nil
+	}
+	sum := md5.New()
+	if _, err := io.Copy(sum, io.NewSectionReader(src, 0, size)); err != nil {
+		return false, err
+	}
+	if string(sum.Sum(nil)) != string(want) {
+		return false, errChecksumMismatch
+	}
+	return true, nil
+}
+
+// storedMD5 reads the object's MD5 from x-goog-hash or from an ETag in the
+// single-part form (32 hex digits), or returns nil.
+func storedMD5(h http.Header) []byte {
+	for _, v := range h.Values("X-Goog-Hash") {
+		for _, part := range strings.Split(v, ",") {
+			if b64, ok := strings.CutPrefix(strings.TrimSpace(part), "md5="); ok {
+				if b, err := base64.StdEncoding.DecodeString(b64); err == nil {
+					return b
+				}
+			}
+		}
+	}
+	etag := strings.Trim(h.Get("ETag"), `"`)
+	if len(etag) == 32 {
+		if b, err := hex.DecodeString(etag); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	c.n += int64(n)
+	return n, err
+}
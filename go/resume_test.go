@@ -0,0 +1,134 @@
+package framequery
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// resumeStorage is a presigned-URL store whose first PUT keeps only the first
+// cut bytes and fails, as a dropped connection would.
+type resumeStorage struct {
+	mu        sync.Mutex
+	ranged    bool   // answers Range requests with 206
+	badETag   bool   // reports an ETag that doesn't match the stored bytes
+	cut       int    // bytes kept by the failing first PUT
+	stored    []byte // object contents
+	puts      []string
+	putRanges []string
+}
+
+func (s *resumeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag := func() string {
+		if s.badETag {
+			return `"` + strings.Repeat("0", 32) + `"`
+		}
+		sum := md5.Sum(s.stored)
+		return `"` + hex.EncodeToString(sum[:]) + `"`
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if s.stored == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", etag())
+		if s.ranged && r.Header.Get("Range") == "bytes=0-0" && len(s.stored) > 0 {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-0/%d", len(s.stored)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(s.stored[:1])
+			return
+		}
+		w.Write(s.stored)
+	case http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		s.puts = append(s.puts, string(b))
+		s.putRanges = append(s.putRanges, r.Header.Get("Content-Range"))
+		if len(s.puts) == 1 {
+			s.stored = b[:s.cut]
+			http.Error(w, "connection reset", http.StatusServiceUnavailable)
+			return
+		}
+		if cr := r.Header.Get("Content-Range"); cr != "" {
+			var from int
+			fmt.Sscanf(cr, "bytes %d-", &from)
+			s.stored = append(s.stored[:from], b...)
+		} else {
+			s.stored = b
+		}
+		w.Header().Set("ETag", etag())
+	}
+}
+
+func TestPutResumable(t *testing.T) {
+	const content = "0123456789abcdefghij"
+	tests := []struct {
+		name       string
+		storage    *resumeStorage
+		wantPuts   []string
+		wantRanges []string
+		unsup      bool
+		wantErr    error
+	}{
+		{
+			name:       "range supported",
+			storage:    &resumeStorage{ranged: true, cut: 12},
+			wantPuts:   []string{content, content[12:]},
+			wantRanges: []string{"", "bytes 12-19/20"},
+		},
+		{
+			name:       "range unsupported",
+			storage:    &resumeStorage{cut: 12},
+			wantPuts:   []string{content, content},
+			wantRanges: []string{"", ""},
+			unsup:      true,
+		},
+		{
+			name:       "checksum mismatch",
+			storage:    &resumeStorage{ranged: true, cut: 12, badETag: true},
+			wantPuts:   []string{content, content[12:]},
+			wantRanges: []string{"", "bytes 12-19/20"},
+			wantErr:    errChecksumMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.storage)
+			defer srv.Close()
+			c := New("k", WithMaxRetries(2))
+
+			stats, err := c.putResumable(context.Background(), srv.URL+"/obj?sig=x", strings.NewReader(content), int64(len(content)), "j", nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			st := tt.storage
+			if fmt.Sprint(st.puts) != fmt.Sprint(tt.wantPuts) || fmt.Sprint(st.putRanges) != fmt.Sprint(tt.wantRanges) {
+				t.Errorf("PUT bodies %q ranges %q, want %q %q", st.puts, st.putRanges, tt.wantPuts, tt.wantRanges)
+			}
+			for _, body := range st.puts {
+				if body == "" {
+					t.Error("sent an empty PUT, which would overwrite the object")
+				}
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if string(st.stored) != content || !stats.Verified || stats.RangeUnsupported != tt.unsup {
+				t.Errorf("stored %q, stats %+v", st.stored, stats)
+			}
+			if want := int64(len(content) + len(tt.wantPuts[1])); stats.BytesSent != want || stats.Attempts != 2 {
+				t.Errorf("stats %+v, want %d bytes over 2 attempts", stats, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package framequery
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ResultFileVersion is the schemaVersion SaveJSON writes. LoadResult reads
+// files up to this version.
+const ResultFileVersion = 1
+
+// resultFile is the document SaveJSON writes: the result in the API's job
+// shape, as ProcessingResult.MarshalJSON encodes it, under a version.
+type resultFile struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Result        json.RawMessage `json:"result"`
+}
+
+// SaveJSON writes r to path as a versioned JSON document. It writes a
+// temporary file in the same directory and renames it over path, so a crash
+// mid-write leaves either the old file or the new one, never a partial one.
+func (r *ProcessingResult) SaveJSON(path string) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("framequery: encode result: %w", err)
+	}
+	b, err = json.MarshalIndent(resultFile{SchemaVersion: ResultFileVersion, Result: b}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("framequery: encode result: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("framequery: save result: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	_, err = tmp.Write(b)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("framequery: save result: %w", err)
+	}
+	return nil
+}
+
+// LoadResult reads a result written by SaveJSON. A file from a newer SDK,
+// with a schemaVersion above ResultFileVersion, is an error.
+func LoadResult(path string) (*ProcessingResult, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("framequery: load result: %w", err)
+	}
+	var f resultFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("framequery: load result %s: %w", path, err)
+	}
+	switch {
+	case f.SchemaVersion == 0 || len(f.Result) == 0:
+		return nil, fmt.Errorf("framequery: load result %s: not a saved result (no schemaVersion or result)", path)
+	case f.SchemaVersion > ResultFileVersion:
+		return nil, fmt.Errorf("framequery: load result %s: schema version %d is newer than this SDK supports (%d); upgrade framequery-go", path, f.SchemaVersion, ResultFileVersion)
+	}
+	var r ProcessingResult
+	if err := json.Unmarshal(f.Result, &r); err != nil {
+		return nil, fmt.Errorf("framequery: load result %s: %w", path, err)
+	}
+	return &r, nil
+}
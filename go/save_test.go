@@ -0,0 +1,70 @@
+package framequery
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSaveLoadResult(t *testing.T) {
+	dir := t.TempDir()
+	for name, fixture := range map[string]string{
+		"full":  fullJobFixture,
+		"empty": `{"jobId":"j","status":"VIDEO_COMPLETED_NO_SCENES","processedData":{"scenes":[],"transcript":[]}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var r ProcessingResult
+			if err := json.Unmarshal([]byte(fixture), &r); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, name+".json")
+			if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.SaveJSON(path); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadResult(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := json.Marshal(r)
+			back, _ := json.Marshal(got)
+			if string(want) != string(back) {
+				t.Errorf("loaded result differs:\n got %s\nwant %s", back, want)
+			}
+			got.Raw, r.Raw = nil, nil
+			if !reflect.DeepEqual(*got, r) {
+				t.Errorf("loaded %+v, want %+v", *got, r)
+			}
+		})
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestLoadResultErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		`{"schemaVersion":99,"result":{}}`: "newer than this SDK",
+		`{"jobId":"j"}`:                    "not a saved result",
+		`{"schemaVersion":1,`:              "unexpected end",
+	}
+	for body, want := range tests {
+		path := filepath.Join(dir, "r.json")
+		os.WriteFile(path, []byte(body), 0o644)
+		if _, err := LoadResult(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadResult(%s) = %v, want error containing %q", body, err, want)
+		}
+	}
+	if _, err := LoadResult(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package framequery
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultDescriptionSimilarity = 0.5
+	defaultObjectSimilarity      = 0.5
+)
+
+// MergeOptions tunes MergeSimilarScenes. Zero thresholds use the defaults (0.5).
+type MergeOptions struct {
+	// DescriptionThreshold is the minimum Jaccard similarity of description
+	// word sets for two adjacent scenes to merge.
+	DescriptionThreshold float64
+	// ObjectThreshold is the minimum Jaccard similarity of object sets.
+	// Two scenes with no objects count as identical.
+	ObjectThreshold float64
+}
+
+// MergeSimilarScenes collapses runs of adjacent scenes whose descriptions and
+// objects are similar enough. A merged scene keeps the first scene's
+// description and start, ends at the last scene's EndTime, joins the distinct
+// summaries, carries the union of keywords, objects, detections and object
+// tracks, and lists the original indices in MergedFrom. The input is not
+// modified.
+func MergeSimilarScenes(scenes []Scene, opts MergeOptions) []Scene {
+	descMin := opts.DescriptionThreshold
+	if descMin <= 0 {
+		descMin = defaultDescriptionSimilarity
+	}
+	objMin := opts.ObjectThreshold
+	if objMin <= 0 {
+		objMin = defaultObjectSimilarity
+	}
+
+	var out []Scene
+	var prevWords, prevObjs map[string]bool
+	for i, s := range scenes {
+		words := wordSet(s.Description)
+		objs := objectSet(s.Objects)
+		if len(out) > 0 && jaccard(prevWords, words) >= descMin && jaccard(prevObjs, objs) >= objMin {
+			m := &out[len(out)-1]
+			m.EndTime = s.EndTime
+			if s.Summary != "" && !strings.Contains(m.Summary, s.Summary) {
+				m.Summary = strings.TrimSpace(m.Summary + " " + s.Summary)
+			}
+			m.Keywords = appendMissing(m.Keywords, s.Keywords)
+			m.Objects = appendMissing(m.Objects, s.Objects)
+			m.Detections = append(m.Detections, s.Detections...)
+			m.ObjectTracks = mergeTracks(m.ObjectTracks, s.ObjectTracks)
+			m.MergedFrom = append(m.MergedFrom, i)
+		} else {
+			merged := s
+			merged.Keywords = append([]string(nil), s.Keywords...)
+			merged.Objects = append([]string(nil), s.Objects...)
+			merged.Detections = append([]ObjectDetection(nil), s.Detections...)
+			merged.ObjectTracks = mergeTracks(nil, s.ObjectTracks)
+			merged.MergedFrom = []int{i}
+			out = append(out, merged)
+		}
+		prevWords, prevObjs = words, objs
+	}
+	return out
+}
+
+// appendMissing appends the entries of add not already in list.
+func appendMissing(list, add []string) []string {
+	for _, v := range add {
+		if !containsString(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// mergeTracks adds tracks to into, joining the spans of tracks with the same
+// label and keeping the higher confidence. Spans are copied, so into never
+// shares memory with tracks.
+func mergeTracks(into, tracks []ObjectTrack) []ObjectTrack {
+	for _, t := range tracks {
+		i := slices.IndexFunc(into, func(m ObjectTrack) bool { return m.Label == t.Label })
+		if i < 0 {
+			into = append(into, ObjectTrack{Label: t.Label, Confidence: t.Confidence})
+			i = len(into) - 1
+		}
+		into[i].Spans = append(into[i].Spans, t.Spans...)
+		into[i].Confidence = max(into[i].Confidence, t.Confidence)
+	}
+	return into
+}
+
+// wordSet lowercases s and splits it on anything that isn't a letter or digit.
+func wordSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[w] = true
+	}
+	return set
+}
+
+func objectSet(objs []string) map[string]bool {
+	set := make(map[string]bool, len(objs))
+	for _, o := range objs {
+		set[strings.ToLower(o)] = true
+	}
+	return set
+}
+
+// jaccard returns |a∩b| / |a∪b|, or 1 when both sets are empty.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	inter := 0
+	for k := range a {
+		if b[k] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package framequery
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadScenes(t *testing.T) []Scene {
+	t.Helper()
+	b, err := os.ReadFile("testdata/scenes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scenes []Scene
+	if err := json.Unmarshal(b, &scenes); err != nil {
+		t.Fatal(err)
+	}
+	return scenes
+}
+
+func TestMergeSimilarScenesGolden(t *testing.T) {
+	scenes := loadScenes(t)
+	merged := MergeSimilarScenes(scenes, MergeOptions{})
+	b, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "scenes_merged.json", string(b)+"\n")
+
+	var from [][]int
+	for _, s := range merged {
+		from = append(from, s.MergedFrom)
+	}
+	if want := [][]int{{0}, {1, 2, 3}, {4, 5}, {6}}; !reflect.DeepEqual(from, want) {
+		t.Fatalf("MergedFrom = %v, want %v", from, want)
+	}
+	podium := merged[1]
+	if podium.StartTime != 4.2 || podium.EndTime != 80.25 {
+		t.Errorf("podium scene spans %v-%v, want 4.2-80.25", podium.StartTime, podium.EndTime)
+	}
+	if len(podium.Detections) != 9 {
+		t.Errorf("%d detections, want all 9 from the three scenes", len(podium.Detections))
+	}
+	if want := []ObjectTrack{
+		{Label: "person", Confidence: 0.99, Spans: []TimeRange{{4.2, 31.5}, {31.5, 58}, {58, 80.25}}},
+		{Label: "microphone", Confidence: 0.84, Spans: []TimeRange{{40, 52}}},
+	}; !reflect.DeepEqual(podium.ObjectTracks, want) {
+		t.Errorf("ObjectTracks = %+v, want %+v", podium.ObjectTracks, want)
+	}
+	if want := []string{"welcome", "host", "keynote"}; !reflect.DeepEqual(podium.Keywords, want) {
+		t.Errorf("Keywords = %v, want %v", podium.Keywords, want)
+	}
+	if want := "The host welcomes the audience. He introduces the keynote."; podium.Summary != want {
+		t.Errorf("Summary = %q, want %q", podium.Summary, want)
+	}
+}
+
+func TestMergeSimilarScenesLeavesInput(t *testing.T) {
+	scenes := loadScenes(t)
+	before := loadScenes(t)
+	merged := MergeSimilarScenes(scenes, MergeOptions{})
+
+	merged[1].ObjectTracks[0].Spans[0].End = -1
+	merged[1].Keywords[0] = "changed"
+	merged[1].Detections[0].Label = "changed"
+	merged[2].Objects[0] = "changed"
+	if !reflect.DeepEqual(scenes, before) {
+		t.Error("editing the merged scenes changed the input")
+	}
+
+	if strict := MergeSimilarScenes(scenes, MergeOptions{DescriptionThreshold: 0.99}); len(strict) != len(scenes) {
+		t.Errorf("%d scenes at threshold 0.99, want all %d unmerged", len(strict), len(scenes))
+	}
+}
+
+func TestDecodeObjectTracks(t *testing.T) {
+	scenes := loadScenes(t)
+	want := []ObjectTrack{
+		{Label: "person", Confidence: 0.98, Spans: []TimeRange{{Start: 31.5, End: 58}}},
+		{Label: "microphone", Confidence: 0.84, Spans: []TimeRange{{Start: 40, End: 52}}},
+	}
+	if got := scenes[2].ObjectTracks; !reflect.DeepEqual(got, want) {
+		t.Errorf("scene 2 tracks = %+v, want %+v", got, want)
+	}
+	if scenes[4].ObjectTracks != nil {
+		t.Errorf("scene 4 tracks = %+v, want none", scenes[4].ObjectTracks)
+	}
+
+	b, err := json.Marshal(scenes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back []Scene
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	for i := range scenes {
+		if !reflect.DeepEqual(back[i].ObjectTracks, scenes[i].ObjectTracks) {
+			t.Errorf("scene %d tracks after a round trip = %+v, want %+v", i, back[i].ObjectTracks, scenes[i].ObjectTracks)
+		}
+	}
+	if n := strings.Count(string(b), `"objectTracks"`); n != 3 {
+		t.Errorf("encoded %d objectTracks keys, want 3 (scenes without tracks omit it)", n)
+	}
+}
+
+func TestObjectTimelineTracks(t *testing.T) {
+	r := &ProcessingResult{Scenes: loadScenes(t)}
+	tests := []struct {
+		label string
+		want  []TimeRange
+	}{
+		// Tracked in scenes 1-3, listed in scenes 4-6
+		{"person", []TimeRange{{4.2, 31.5}, {31.5, 58}, {58, 80.25}, {80.25, 86}, {86, 90}, {90, 121}}},
+		// Listed without a track in scene 1, tracked in scene 2
+		{"microphone", []TimeRange{{4.2, 31.5}, {40, 52}}},
+		{"logo", []TimeRange{{0, 4.2}}},
+		{"giraffe", nil},
+	}
+	for _, tt := range tests {
+		if got := r.ObjectTimeline(tt.label); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ObjectTimeline(%q) = %v, want %v", tt.label, got, tt.want)
+		}
+	}
+
+	// Spans come back in time order even when a scene lists them out of order
+	r = &ProcessingResult{Scenes: []Scene{{EndTime: 10, ObjectTracks: []ObjectTrack{
+		{Label: "dog", Spans: []TimeRange{{6, 8}}},
+		{Label: "dog", Spans: []TimeRange{{1, 3}}},
+	}}}}
+	if got, want := r.ObjectTimeline("dog"), []TimeRange{{1, 3}, {6, 8}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectTimeline(dog) = %v, want %v", got, want)
+	}
+}
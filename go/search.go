@@ -0,0 +1,98 @@
+package framequery
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TranscriptIndex answers word searches over a transcript without scanning
+// every segment. The word list is built on the first Search and reused after,
+// so repeated searches of a long transcript cost time in the number of
+// matches. Safe for concurrent use.
+type TranscriptIndex struct {
+	segs []TranscriptSegment
+
+	once  sync.Once
+	words []indexedWord // sorted by word, then segment
+}
+
+type indexedWord struct {
+	word string
+	seg  int
+}
+
+// NewTranscriptIndex returns an index over r's transcript. Edits to the
+// transcript afterwards are not seen.
+func (r *ProcessingResult) NewTranscriptIndex() *TranscriptIndex {
+	return &TranscriptIndex{segs: append([]TranscriptSegment(nil), r.Transcript...)}
+}
+
+// Search returns the segments, in transcript order, in which every word of
+// query begins some word, ignoring case: "deploy prod" matches "Deploying
+// to production". Unlike SearchTranscript, it matches at word starts only.
+func (idx *TranscriptIndex) Search(query string) []TranscriptSegment {
+	idx.once.Do(idx.build)
+	terms := splitWords(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	var hits map[int]bool
+	for _, term := range terms {
+		found := idx.prefixed(term)
+		if hits != nil {
+			for seg := range hits {
+				if !found[seg] {
+					delete(hits, seg)
+				}
+			}
+		} else {
+			hits = found
+		}
+		if len(hits) == 0 {
+			return nil
+		}
+	}
+	ids := make([]int, 0, len(hits))
+	for seg := range hits {
+		ids = append(ids, seg)
+	}
+	sort.Ints(ids)
+	out := make([]TranscriptSegment, len(ids))
+	for i, seg := range ids {
+		out[i] = idx.segs[seg]
+	}
+	return out
+}
+
+// prefixed returns the segments holding a word that starts with prefix.
+func (idx *TranscriptIndex) prefixed(prefix string) map[int]bool {
+	i := sort.Search(len(idx.words), func(i int) bool { return idx.words[i].word >= prefix })
+	found := make(map[int]bool)
+	for ; i < len(idx.words) && strings.HasPrefix(idx.words[i].word, prefix); i++ {
+		found[idx.words[i].seg] = true
+	}
+	return found
+}
+
+func (idx *TranscriptIndex) build() {
+	for i, t := range idx.segs {
+		for _, w := range splitWords(t.Text) {
+			idx.words = append(idx.words, indexedWord{word: w, seg: i})
+		}
+	}
+	sort.Slice(idx.words, func(a, b int) bool {
+		if idx.words[a].word != idx.words[b].word {
+			return idx.words[a].word < idx.words[b].word
+		}
+		return idx.words[a].seg < idx.words[b].seg
+	})
+}
+
+// splitWords lowercases s and splits it into runs of letters and digits.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,87 @@
+package framequery
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+var searchResult = &ProcessingResult{Transcript: []TranscriptSegment{
+	{StartTime: 0, EndTime: 2, Text: "Deploying to production today"},
+	{StartTime: 2, EndTime: 4, Text: "the PROD database is down"},
+	{StartTime: 4, EndTime: 6, Text: "Reproduce it locally"},
+	{StartTime: 6, EndTime: 8, Text: ""},
+}}
+
+func texts(segs []TranscriptSegment) []string {
+	out := []string{}
+	for _, s := range segs {
+		out = append(out, s.Text)
+	}
+	return out
+}
+
+func TestSearchTranscript(t *testing.T) {
+	tests := []struct {
+		query         string
+		caseSensitive bool
+		want          []string
+	}{
+		{"prod", false, []string{"Deploying to production today", "the PROD database is down", "Reproduce it locally"}},
+		{"prod", true, []string{"Deploying to production today", "Reproduce it locally"}},
+		{"PROD", true, []string{"the PROD database is down"}},
+		{"missing", false, []string{}},
+	}
+	for _, tt := range tests {
+		got := texts(searchResult.SearchTranscript(tt.query, tt.caseSensitive))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SearchTranscript(%q, %v) = %q, want %q", tt.query, tt.caseSensitive, got, tt.want)
+		}
+	}
+
+	got := texts(searchResult.SearchTranscriptRegex(regexp.MustCompile(`(?i)\bdown$|^Dep`)))
+	if want := []string{"Deploying to production today", "the PROD database is down"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchTranscriptRegex = %q, want %q", got, want)
+	}
+}
+
+func TestTranscriptIndex(t *testing.T) {
+	idx := searchResult.NewTranscriptIndex()
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"prod", []string{"Deploying to production today", "the PROD database is down"}}, // not "Reproduce"
+		{"deploy prod", []string{"Deploying to production today"}},
+		{"prod deploy", []string{"Deploying to production today"}},
+		{"DATABASE", []string{"the PROD database is down"}},
+		{"prod local", []string{}},
+		{"  ", []string{}},
+	}
+	for _, tt := range tests {
+		if got := texts(idx.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkTranscriptSearch(b *testing.B) {
+	r := &ProcessingResult{}
+	for i := 0; i < 50000; i++ {
+		r.Transcript = append(r.Transcript, TranscriptSegment{StartTime: float64(i), EndTime: float64(i + 1), Text: fmt.Sprintf("segment %d talks about topic%d", i, i%997)})
+	}
+	b.Run("scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r.SearchTranscript("topic42", false)
+		}
+	})
+	b.Run("index", func(b *testing.B) {
+		idx := r.NewTranscriptIndex()
+		idx.Search("warm")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			idx.Search("topic42")
+		}
+	})
+}
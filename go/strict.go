@@ -0,0 +1,118 @@
+package framequery
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// WithStrictParsing makes job responses fail with an error when they contain a
+// field the SDK doesn't know about, instead of keeping it only in Raw. Meant for
+// integration tests that should catch API changes; leave it off in production.
+func WithStrictParsing() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// strictJob lists every job field the SDK parses.
+type strictJob struct {
+	JobID                string               `json:"jobId"`
+	Status               string               `json:"status"`
+	OriginalFilename     string               `json:"originalFilename"`
+	CreatedAt            string               `json:"createdAt"`
+	ETASeconds           *float64             `json:"estimatedCompletionTimeSeconds"`
+	AudioTrackCount      *int                 `json:"audioTrackCount"`
+	AudioTracksCompleted *int                 `json:"audioTracksCompleted"`
+	AudioTrackNames      []string             `json:"audioTrackNames"`
+	DisplayName          string               `json:"displayName"`
+	Metadata             map[string]string    `json:"metadata"`
+	Tags                 []string             `json:"tags"`
+	Features             []string             `json:"features"`
+	DetectedLanguage     string               `json:"detectedLanguage"`
+	ErrorMessage         string               `json:"errorMessage"`
+	ErrorCode            string               `json:"errorCode"`
+	VideoMetadata        *strictVideoMetadata `json:"videoMetadata"`
+	ProcessedData        *strictProcessedData `json:"processedData"`
+}
+
+type strictVideoMetadata struct {
+	Width         int       `json:"width"`
+	Height        int       `json:"height"`
+	FPS           strictFPS `json:"fps"`
+	Codec         string    `json:"codec"`
+	Bitrate       int64     `json:"bitrate"`
+	AudioChannels int       `json:"audioChannels"`
+}
+
+// strictFPS is a frame rate as parseFPS accepts it: a number or "num/den".
+type strictFPS float64
+
+func (f *strictFPS) UnmarshalJSON(b []byte) error {
+	v, err := parseFPS(b)
+	*f = strictFPS(v)
+	return err
+}
+
+type strictProcessedData struct {
+	Length           float64         `json:"length"`
+	DetectedLanguage string          `json:"detectedLanguage"`
+	Summary          string          `json:"summary"`
+	Keywords         []string        `json:"keywords"`
+	Topics           []string        `json:"topics"`
+	Scenes           []strictScene   `json:"scenes"`
+	Transcript       []strictSegment `json:"transcript"`
+}
+
+type strictScene struct {
+	Description  string          `json:"description"`
+	Summary      string          `json:"summary"`
+	Keywords     []string        `json:"keywords"`
+	StartTs      float64         `json:"startTs"`
+	EndTs        float64         `json:"endTs"`
+	KeyFrameURL  string          `json:"keyFrameUrl"`
+	Objects      json.RawMessage `json:"objects"` // labels or {label, confidence} objects
+	ObjectTracks []struct {
+		Label      string   `json:"label"`
+		Confidence *float64 `json:"confidence"`
+		Spans      []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+		} `json:"spans"`
+	} `json:"objectTracks"`
+}
+
+type strictSegment struct {
+	StartTime float64 `json:"StartTime"`
+	EndTime   float64 `json:"EndTime"`
+	Text      string  `json:"Text"`
+	Speaker   string  `json:"Speaker"`
+	StartTs   float64 `json:"startTs"`
+	EndTs     float64 `json:"endTs"`
+}
+
+// checkStrict reports an error for unknown fields in a job payload when
+// strict parsing is on.
+func (c *Client) checkStrict(b []byte) error {
+	if !c.strict {
+		return nil
+	}
+	if err := checkJobShape(b); err != nil {
+		return fmt.Errorf("framequery: strict parsing: %w", err)
+	}
+	return nil
+}
+
+// checkJobShape reports job fields the SDK doesn't know, and known fields of
+// the wrong type.
+func checkJobShape(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(&strictJob{})
+}
+
+// checkJobTypes reports known job fields of the wrong type, which decodeJob
+// leaves zero.
+func checkJobTypes(b []byte) error {
+	return json.Unmarshal(b, &strictJob{})
+}
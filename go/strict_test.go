@@ -0,0 +1,80 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// malformedPage holds a good job, a non-object, a job whose status has the
+// wrong type, and a job with a field the SDK doesn't know.
+const malformedPage = `{"data":[
+	{"jobId":"j1","status":"QUEUED"},
+	"oops",
+	{"jobId":"j3","status":5},
+	{"jobId":"j4","status":"QUEUED","brandNewField":true}
+]}`
+
+func TestListJobsStrict(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(malformedPage))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+	c := New("k", WithBaseURL(srv.URL))
+
+	page, err := c.ListJobs(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Jobs) != 3 || len(page.DecodeErrors) != 0 {
+		t.Fatalf("lenient: %d jobs, %d decode errors; want 3 and none", len(page.Jobs), len(page.DecodeErrors))
+	}
+	if j := page.Jobs[1]; j.ID != "j3" || j.Status != "" {
+		t.Errorf("lenient: second job = %s with status %q, want j3 with none", j.ID, j.Status)
+	}
+
+	page, err = c.ListJobs(ctx, &ListJobsOptions{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Jobs) != 2 || len(page.DecodeErrors) != 2 {
+		t.Fatalf("Strict: %d jobs, %d decode errors; want 2 and 2", len(page.Jobs), len(page.DecodeErrors))
+	}
+	if de := page.DecodeErrors[0]; de.Index != 1 || de.Item != "oops" {
+		t.Errorf("first decode error = %+v, want index 1 holding the raw item", de)
+	}
+	if de := page.DecodeErrors[1]; de.Index != 2 {
+		t.Errorf("second decode error at index %d, want 2", de.Index)
+	} else if item, ok := de.Item.(map[string]any); !ok || item["jobId"] != "j3" {
+		t.Errorf("second decode error item = %v, want the raw j3 object", de.Item)
+	}
+
+	_, err = New("k", WithBaseURL(srv.URL), WithStrictParsing()).ListJobs(ctx, nil)
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Index != 1 {
+		t.Errorf("WithStrictParsing: err = %v, want a *DecodeError for item 1", err)
+	}
+}
+
+func TestGetJobWrongTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":` + wrongTypesJob + `}`))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	job, err := New("k", WithBaseURL(srv.URL)).GetJob(ctx, "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusQueued || job.Filename != "a.mp4" || job.ETASeconds != 0 {
+		t.Errorf("job = %+v, want the wrong-typed fields zero and the rest kept", job)
+	}
+
+	if _, err := New("k", WithBaseURL(srv.URL), WithStrictParsing()).GetJob(ctx, "j1"); err == nil {
+		t.Error("WithStrictParsing: want an error")
+	}
+}
@@ -0,0 +1,315 @@
+package framequery
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSubtitleLine is the longest line most subtitle renderers show untruncated.
+const maxSubtitleLine = 80
+
+// DefaultMaxCueDuration is the longest cue WriteSRT and WriteVTT emit before
+// splitting a segment, the usual subtitle guideline.
+const DefaultMaxCueDuration = 7 * time.Second
+
+// minCueMillis is the on-screen time given to a zero-length segment, less if
+// the next cue starts sooner.
+const minCueMillis = 1000
+
+type cue struct {
+	start, end int64 // milliseconds
+	parts      []cuePart
+}
+
+// cuePart is the text of one speaker within a cue. Speaker is empty when
+// the transcript has no diarization.
+type cuePart struct {
+	speaker, text string
+}
+
+// text returns the cue's text with speakers dropped.
+func (c cue) text() string {
+	texts := make([]string, len(c.parts))
+	for i, p := range c.parts {
+		texts[i] = p.text
+	}
+	return strings.Join(texts, " ")
+}
+
+// addText appends text said by speaker, extending the last part if the
+// speaker is the same.
+func (c *cue) addText(speaker, text string) {
+	if n := len(c.parts); n > 0 && c.parts[n-1].speaker == speaker {
+		c.parts[n-1].text += " " + text
+		return
+	}
+	c.parts = append(c.parts, cuePart{speaker: speaker, text: text})
+}
+
+// SRTOptions configures WriteSRTOptions.
+type SRTOptions struct {
+	// MaxCueDuration splits longer segments into several cues, dividing the
+	// words and time between them. Zero means DefaultMaxCueDuration; negative
+	// never splits.
+	MaxCueDuration time.Duration
+}
+
+// WriteSRT writes the transcript as SubRip subtitles with the default
+// options. See WriteSRTOptions.
+func (r *ProcessingResult) WriteSRT(w io.Writer) error {
+	return r.WriteSRTOptions(w, nil)
+}
+
+// WriteSRTOptions writes the transcript as SubRip subtitles: numbered cues
+// timed HH:MM:SS,mmm, lines wrapped at 80 characters. A segment that overlaps
+// the next has its end clamped to the next one's start; segments starting at
+// the same time share a cue. A zero-length segment is shown for a second, or
+// until the next cue.
+func (r *ProcessingResult) WriteSRTOptions(w io.Writer, opts *SRTOptions) error {
+	if opts == nil {
+		opts = &SRTOptions{}
+	}
+	bw := bufio.NewWriter(w)
+	for i, c := range r.cues(opts.MaxCueDuration) {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1, subtitleTime(c.start, ','), subtitleTime(c.end, ','), wrapLines(c.text(), maxSubtitleLine))
+	}
+	return bw.Flush()
+}
+
+// TranscriptSRT returns the transcript as SubRip subtitles, as WriteSRT
+// writes them.
+func (r *ProcessingResult) TranscriptSRT() (string, error) {
+	var b strings.Builder
+	if err := r.WriteSRT(&b); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// WriteVTT writes the transcript as WebVTT, with a NOTE block giving the job
+// ID and duration when known. Cues are built as in WriteSRTOptions, with the
+// default options.
+func (r *ProcessingResult) WriteVTT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	if r.JobID != "" || r.Duration > 0 {
+		bw.WriteString("NOTE\n")
+		if r.JobID != "" {
+			fmt.Fprintf(bw, "job %s\n", r.JobID)
+		}
+		if r.Duration > 0 {
+			fmt.Fprintf(bw, "duration %s\n", subtitleTime(secondsToMillis(r.Duration), '.'))
+		}
+		bw.WriteString("\n")
+	}
+	writeVTTCues(bw, r.cues(0), &VTTOptions{})
+	return bw.Flush()
+}
+
+// VTTOptions configures TranscriptVTT.
+type VTTOptions struct {
+	// CueIdentifiers numbers the cues from 1.
+	CueIdentifiers bool
+	// VoiceTags marks each speaker's text with a <v Speaker> tag, for
+	// transcripts with TranscriptSegment.Speaker set. Each speaker in a cue
+	// starts a new line, and is closed with </v> if others follow.
+	VoiceTags bool
+	// MaxLineLength wraps cue text at word boundaries. Zero means 80
+	// characters; negative never wraps.
+	MaxLineLength int
+	// MaxCueDuration is as in SRTOptions.
+	MaxCueDuration time.Duration
+}
+
+// TranscriptVTT writes the transcript as WebVTT, for HTML5 <track> elements:
+// the WEBVTT header, then cues timed HH:MM:SS.mmm and built as in
+// WriteSRTOptions. An empty transcript writes just the header. Unlike
+// WriteVTT there is no NOTE block.
+func (r *ProcessingResult) TranscriptVTT(w io.Writer, opts *VTTOptions) error {
+	if opts == nil {
+		opts = &VTTOptions{}
+	}
+	cues := r.cues(opts.MaxCueDuration)
+	bw := bufio.NewWriter(w)
+	if len(cues) == 0 {
+		bw.WriteString("WEBVTT\n")
+		return bw.Flush()
+	}
+	bw.WriteString("WEBVTT\n\n")
+	writeVTTCues(bw, cues, opts)
+	return bw.Flush()
+}
+
+// writeVTTCues writes cues, each followed by a blank line.
+func writeVTTCues(bw *bufio.Writer, cues []cue, opts *VTTOptions) {
+	width := opts.MaxLineLength
+	switch {
+	case width == 0:
+		width = maxSubtitleLine
+	case width < 0:
+		width = math.MaxInt
+	}
+	for i, c := range cues {
+		if opts.CueIdentifiers {
+			fmt.Fprintf(bw, "%d\n", i+1)
+		}
+		fmt.Fprintf(bw, "%s --> %s\n", subtitleTime(c.start, '.'), subtitleTime(c.end, '.'))
+		if !opts.VoiceTags {
+			bw.WriteString(vttEscaper.Replace(wrapLines(c.text(), width)))
+			bw.WriteString("\n\n")
+			continue
+		}
+		for _, p := range c.parts {
+			if p.speaker != "" {
+				fmt.Fprintf(bw, "<v %s>", vttEscaper.Replace(strings.Join(strings.Fields(p.speaker), " ")))
+			}
+			bw.WriteString(vttEscaper.Replace(wrapLines(p.text, width)))
+			if p.speaker != "" && len(c.parts) > 1 {
+				bw.WriteString("</v>") // or the next speaker's span nests in this one
+			}
+			bw.WriteString("\n")
+		}
+		bw.WriteString("\n")
+	}
+}
+
+// vttEscaper escapes the characters WebVTT cue text reserves, which also
+// keeps "-->" out of a cue.
+var vttEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// cues returns the non-empty transcript segments in start order as
+// non-overlapping cues, split at maxDur (see SRTOptions.MaxCueDuration).
+func (r *ProcessingResult) cues(maxDur time.Duration) []cue {
+	segs := make([]TranscriptSegment, 0, len(r.Transcript))
+	for _, t := range r.Transcript {
+		if strings.TrimSpace(t.Text) != "" {
+			segs = append(segs, t)
+		}
+	}
+	sort.SliceStable(segs, func(i, j int) bool { return segs[i].StartTime < segs[j].StartTime })
+
+	var out []cue
+	for _, t := range segs {
+		start, end, text := t.StartMillis(), t.EndMillis(), strings.TrimSpace(t.Text)
+		if end <= start {
+			end = start + minCueMillis
+		}
+		if n := len(out); n > 0 {
+			last := &out[n-1]
+			if start == last.start {
+				// Clamping would leave nothing of the earlier cue
+				last.end = max(last.end, end)
+				last.addText(t.Speaker, text)
+				continue
+			}
+			last.end = min(last.end, start)
+		}
+		out = append(out, cue{start: start, end: end, parts: []cuePart{{speaker: t.Speaker, text: text}}})
+	}
+
+	if maxDur == 0 {
+		maxDur = DefaultMaxCueDuration
+	}
+	if maxDur < 0 {
+		return out
+	}
+	split := make([]cue, 0, len(out))
+	for _, c := range out {
+		split = append(split, splitCue(c, maxDur.Milliseconds())...)
+	}
+	return split
+}
+
+// splitCue divides c into the fewest cues of at most maxMs each, giving each
+// an equal share of the time and of the words. A cue with fewer words than
+// that gets one cue per word, which may run longer than maxMs.
+func splitCue(c cue, maxMs int64) []cue {
+	dur := c.end - c.start
+	if maxMs <= 0 || dur <= maxMs {
+		return []cue{c}
+	}
+	var words []cuePart // one word each
+	for _, p := range c.parts {
+		for _, w := range strings.Fields(p.text) {
+			words = append(words, cuePart{speaker: p.speaker, text: w})
+		}
+	}
+	n := int((dur + maxMs - 1) / maxMs)
+	n = min(n, len(words))
+	if n <= 1 {
+		return []cue{c}
+	}
+	out := make([]cue, n)
+	for i := range out {
+		out[i].start = c.start + dur*int64(i)/int64(n)
+		out[i].end = c.start + dur*int64(i+1)/int64(n)
+		for _, w := range words[i*len(words)/n : (i+1)*len(words)/n] {
+			out[i].addText(w.speaker, w.text)
+		}
+	}
+	return out
+}
+
+// subtitleTime formats ms as HH:MM:SS<sep>mmm. SRT uses ',' and WebVTT '.'.
+func subtitleTime(ms int64, sep byte) string {
+	if ms < 0 {
+		ms = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// wrapLines breaks text at word boundaries into lines of at most width
+// characters. A single longer word gets a line to itself.
+func wrapLines(text string, width int) string {
+	var b strings.Builder
+	lineLen := 0
+	for _, word := range strings.Fields(text) {
+		n := len([]rune(word))
+		switch {
+		case lineLen == 0:
+		case lineLen+1+n > width:
+			b.WriteByte('\n')
+			lineLen = 0
+		default:
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += n
+	}
+	return b.String()
+}
+
+// WriteChaptersVTT writes the scenes as a WebVTT chapters file, for
+// <track kind="chapters">: one cue per scene, titled with its Description
+// ("Scene N" if it has none). A NOTE block gives the job ID. A scene without
+// a StartTime starts where the previous one ended; scenes with no length are
+// skipped.
+func (r *ProcessingResult) WriteChaptersVTT(w io.Writer) error {
+	scenes := sceneSpans(r.Scenes)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	if r.JobID != "" {
+		fmt.Fprintf(bw, "NOTE\njob %s\n\n", r.JobID)
+	}
+	n := 0
+	for _, s := range scenes {
+		start, end := s.StartMillis(), s.EndMillis()
+		if end <= start {
+			continue
+		}
+		n++
+		title := strings.Join(strings.Fields(s.Description), " ")
+		if title == "" {
+			title = fmt.Sprintf("Scene %d", n)
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", n, subtitleTime(start, '.'), subtitleTime(end, '.'), vttEscaper.Replace(title))
+	}
+	return bw.Flush()
+}
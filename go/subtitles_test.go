@@ -0,0 +1,222 @@
+package framequery
+
+import (
+	"bytes"
+	"flag"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata golden files")
+
+// checkGolden compares got with testdata/name, or rewrites it with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch:\n got:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// subtitleFixture has the awkward cases: out of order, overlapping, a shared
+// start, zero-length, blank, and one segment long enough to split.
+var subtitleFixture = &ProcessingResult{Transcript: []TranscriptSegment{
+	{StartTime: 4.5, EndTime: 6, Text: "Second, out of order."},
+	{StartTime: 0, EndTime: 2.0004, Text: " Welcome to the show. "},
+	{StartTime: 1.5, EndTime: 4, Text: "This overlaps the first."},
+	{StartTime: 6, EndTime: 6, Text: "Blink."},
+	{StartTime: 6.5, EndTime: 6.5, Text: "   "},
+	{StartTime: 6.5, EndTime: 8, Text: "Same start,"},
+	{StartTime: 6.5, EndTime: 7.5, Text: "shared cue."},
+	{StartTime: 3725.25, EndTime: 3741.25, Text: "A long closing statement that runs well past the seven second limit and has to be split into three cues."},
+}}
+
+func TestTranscriptSRTGolden(t *testing.T) {
+	got, err := subtitleFixture.TranscriptSRT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "transcript.srt", got)
+
+	var buf bytes.Buffer
+	if err := subtitleFixture.WriteSRT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != got {
+		t.Error("WriteSRT and TranscriptSRT differ")
+	}
+}
+
+func TestTranscriptSRTUnicodeGolden(t *testing.T) {
+	r := &ProcessingResult{Transcript: []TranscriptSegment{
+		{StartTime: 0, EndTime: 1.25, Text: "こんにちは、世界。"},
+		{StartTime: 1.25, EndTime: 3, Text: "Ça va? Très bien — merci! 🎬"},
+		{StartTime: 3, EndTime: 5.5, Text: "Ελληνικά και русский текст в одной строке, которая достаточно длинная, чтобы её перенести."},
+	}}
+	got, err := r.TranscriptSRT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "unicode.srt", got)
+}
+
+func TestSRTMaxCueDuration(t *testing.T) {
+	r := &ProcessingResult{Transcript: []TranscriptSegment{{StartTime: 0, EndTime: 20, Text: "one two three four five six"}}}
+	tests := []struct {
+		max  time.Duration
+		cues int
+	}{
+		{0, 3}, // default 7s
+		{5 * time.Second, 4},
+		{-1, 1},
+		{time.Second, 6}, // one cue per word at most
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := r.WriteSRTOptions(&buf, &SRTOptions{MaxCueDuration: tt.max}); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(buf.String(), " --> "); got != tt.cues {
+			t.Errorf("MaxCueDuration %v: %d cues, want %d:\n%s", tt.max, got, tt.cues, buf.String())
+		}
+	}
+
+	if got, _ := (&ProcessingResult{}).TranscriptSRT(); got != "" {
+		t.Errorf("empty transcript: %q", got)
+	}
+}
+
+var vttTiming = regexp.MustCompile(`^(\d{2,}):([0-5]\d):([0-5]\d)\.(\d{3}) --> (\d{2,}):([0-5]\d):([0-5]\d)\.(\d{3})$`)
+
+// validateVTT checks the WebVTT rules a browser enforces on a cue file: the
+// header, cue and NOTE blocks separated by blank lines, well-formed timings in start
+// order with start before end, and cue text free of "-->" and of "<" or "&"
+// outside voice tags and entities.
+func validateVTT(t *testing.T, doc string) {
+	t.Helper()
+	if doc != "WEBVTT\n" && !strings.HasPrefix(doc, "WEBVTT\n\n") {
+		t.Fatalf("bad header: %q", doc)
+	}
+	if !strings.HasSuffix(doc, "\n") {
+		t.Error("missing final newline")
+	}
+	ms := func(m []string) int64 {
+		n := func(s string) int64 { v, _ := strconv.ParseInt(s, 10, 64); return v }
+		return ((n(m[0])*60+n(m[1]))*60+n(m[2]))*1000 + n(m[3])
+	}
+	var lastStart int64 = -1
+	for _, block := range strings.Split(strings.TrimSpace(doc), "\n\n")[1:] {
+		lines := strings.Split(block, "\n")
+		if lines[0] == "NOTE" || strings.HasPrefix(lines[0], "NOTE ") {
+			continue
+		}
+		if !strings.Contains(lines[0], "-->") {
+			lines = lines[1:] // cue identifier
+		}
+		m := vttTiming.FindStringSubmatch(lines[0])
+		if m == nil {
+			t.Errorf("bad timing line %q", lines[0])
+			continue
+		}
+		start, end := ms(m[1:5]), ms(m[5:9])
+		if start >= end || start < lastStart {
+			t.Errorf("cue %q: start %d, end %d, previous start %d", lines[0], start, end, lastStart)
+		}
+		lastStart = start
+		if len(lines) < 2 {
+			t.Errorf("cue %q has no text", lines[0])
+		}
+		for _, l := range lines[1:] {
+			text := regexp.MustCompile(`^<v [^>\n]+>|</v>$`).ReplaceAllString(l, "")
+			text = regexp.MustCompile(`&(amp|lt|gt);`).ReplaceAllString(text, "")
+			if l == "" || strings.Contains(l, "-->") || strings.ContainsAny(text, "<&") {
+				t.Errorf("bad cue text %q", l)
+			}
+		}
+	}
+}
+
+func TestTranscriptVTT(t *testing.T) {
+	r := &ProcessingResult{Transcript: []TranscriptSegment{
+		{StartTime: 0, EndTime: 2.5, Text: "Hi, I'm Ana & this is <live>.", Speaker: "Ana"},
+		{StartTime: 2, EndTime: 4, Text: "And I'm Bo --> welcome!", Speaker: "Bo"},
+		{StartTime: 4, EndTime: 6, Text: "Both of us,", Speaker: "Ana"},
+		{StartTime: 4, EndTime: 6, Text: "together.", Speaker: "Bo"},
+		{StartTime: 6, EndTime: 7.25, Text: "No speaker on this one, and it is long enough to wrap."},
+	}}
+	var buf bytes.Buffer
+	if err := r.TranscriptVTT(&buf, &VTTOptions{CueIdentifiers: true, VoiceTags: true, MaxLineLength: 32}); err != nil {
+		t.Fatal(err)
+	}
+	validateVTT(t, buf.String())
+	checkGolden(t, "transcript.vtt", buf.String())
+	for _, l := range strings.Split(buf.String(), "\n") {
+		if text := regexp.MustCompile(`^<v [^>]+>|</v>$`).ReplaceAllString(l, ""); len([]rune(html.UnescapeString(text))) > 32 {
+			t.Errorf("line %q longer than 32 characters", l)
+		}
+	}
+
+	buf.Reset()
+	if err := r.TranscriptVTT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	validateVTT(t, buf.String())
+	if strings.Contains(buf.String(), "<v ") || strings.Contains(buf.String(), "\n1\n") {
+		t.Errorf("default options added voice tags or identifiers:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := (&ProcessingResult{}).TranscriptVTT(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "WEBVTT\n" {
+		t.Errorf("empty transcript: %q, want just the header", buf.String())
+	}
+
+	buf.Reset()
+	withNote := *subtitleFixture
+	withNote.JobID, withNote.Duration = "job_1", 3741.25
+	if err := withNote.WriteVTT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	validateVTT(t, buf.String())
+}
+
+func TestWriteChaptersVTT(t *testing.T) {
+	r := &ProcessingResult{JobID: "job_42", Scenes: []Scene{
+		{Description: "Kitchen tour", EndTime: 12.5},
+		{Description: "  Chopping\n onions & garlic ", StartTime: 12.5, EndTime: 47},
+		{EndTime: 47}, // zero length, skipped
+		{Description: "", EndTime: 3600.001},
+	}}
+	var buf bytes.Buffer
+	if err := r.WriteChaptersVTT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "WEBVTT\n\nNOTE\njob job_42\n\n" +
+		"1\n00:00:00.000 --> 00:00:12.500\nKitchen tour\n\n" +
+		"2\n00:00:12.500 --> 00:00:47.000\nChopping onions &amp; garlic\n\n" +
+		"3\n00:00:47.000 --> 01:00:00.001\nScene 3\n\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	validateVTT(t, buf.String())
+	if r.Scenes[3].StartTime != 0 {
+		t.Error("WriteChaptersVTT modified the result's scenes")
+	}
+}
@@ -0,0 +1,151 @@
+package framequery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png" // key frames may be PNG
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ThumbTrackOptions configures GenerateThumbnailTrack. Zero values use the
+// defaults: 160x90 tiles, 10 columns, JPEG quality 75, sprite "sprite.jpg".
+type ThumbTrackOptions struct {
+	TileWidth   int
+	TileHeight  int
+	Columns     int
+	JPEGQuality int
+	SpriteURL   string // how the WebVTT refers to the sprite image
+}
+
+// ThumbnailTrack is a sprite sheet of scene key frames and the WebVTT track
+// mapping each scene's time range to its tile, for player scrub previews.
+// Missing lists the indices of scenes whose key frame couldn't be fetched or
+// decoded; their tiles are left blank.
+type ThumbnailTrack struct {
+	Sprite  []byte // JPEG
+	VTT     []byte
+	Missing []int
+}
+
+// GenerateThumbnailTrack fetches the key frame of every scene in a completed
+// job and composes them into a sprite sheet, one tile per scene in order.
+func (c *Client) GenerateThumbnailTrack(ctx context.Context, jobID string, opts *ThumbTrackOptions) (*ThumbnailTrack, error) {
+	o := ThumbTrackOptions{TileWidth: 160, TileHeight: 90, Columns: 10, JPEGQuality: 75, SpriteURL: "sprite.jpg"}
+	if opts != nil {
+		if opts.TileWidth > 0 {
+			o.TileWidth = opts.TileWidth
+		}
+		if opts.TileHeight > 0 {
+			o.TileHeight = opts.TileHeight
+		}
+		if opts.Columns > 0 {
+			o.Columns = opts.Columns
+		}
+		if opts.JPEGQuality > 0 {
+			o.JPEGQuality = opts.JPEGQuality
+		}
+		if opts.SpriteURL != "" {
+			o.SpriteURL = opts.SpriteURL
+		}
+	}
+
+	result, err := c.GetResult(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	scenes := sceneSpans(result.Scenes)
+	if len(scenes) == 0 {
+		return nil, fmt.Errorf("framequery: job %s has no scenes", jobID)
+	}
+
+	cols := o.Columns
+	if len(scenes) < cols {
+		cols = len(scenes)
+	}
+	rows := (len(scenes) + cols - 1) / cols
+	sprite := image.NewRGBA(image.Rect(0, 0, cols*o.TileWidth, rows*o.TileHeight))
+
+	missing := make([]bool, len(scenes))
+	sem := make(chan struct{}, c.fetchConcurrency)
+	var wg sync.WaitGroup
+	for i := range scenes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			img, err := c.fetchImage(ctx, scenes[i].KeyFrameURL)
+			if err != nil {
+				missing[i] = true
+				return
+			}
+			// Tiles don't overlap, so concurrent draws are safe
+			x, y := (i%cols)*o.TileWidth, (i/cols)*o.TileHeight
+			drawScaled(sprite, image.Rect(x, y, x+o.TileWidth, y+o.TileHeight), img)
+		}(i)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	track := &ThumbnailTrack{}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, sprite, &jpeg.Options{Quality: o.JPEGQuality}); err != nil {
+		return nil, fmt.Errorf("framequery: encode sprite: %w", err)
+	}
+	track.Sprite = buf.Bytes()
+
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n\n")
+	for i, s := range scenes {
+		if missing[i] {
+			track.Missing = append(track.Missing, i)
+		}
+		x, y := (i%cols)*o.TileWidth, (i/cols)*o.TileHeight
+		fmt.Fprintf(&vtt, "%s --> %s\n%s#xywh=%d,%d,%d,%d\n\n",
+			subtitleTime(s.StartMillis(), '.'), subtitleTime(s.EndMillis(), '.'), o.SpriteURL, x, y, o.TileWidth, o.TileHeight)
+	}
+	track.VTT = []byte(vtt.String())
+	return track, nil
+}
+
+// fetchImage downloads and decodes an image from a signed URL.
+func (c *Client) fetchImage(ctx context.Context, u string) (image.Image, error) {
+	if u == "" {
+		return nil, fmt.Errorf("framequery: no key frame URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("framequery: fetch key frame: %s", resp.Status)
+	}
+	img, _, err := image.Decode(resp.Body)
+	return img, err
+}
+
+// drawScaled draws src into r of dst with nearest-neighbour scaling.
+func drawScaled(dst draw.Image, r image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	w, h := r.Dx(), r.Dy()
+	for y := 0; y < h; y++ {
+		sy := sb.Min.Y + y*sb.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := sb.Min.X + x*sb.Dx()/w
+			dst.Set(r.Min.X+x, r.Min.Y+y, src.At(sx, sy))
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package framequery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateThumbnailTrack(t *testing.T) {
+	frames := map[string]color.RGBA{"/kf/0.png": {255, 0, 0, 255}, "/kf/1.png": {0, 0, 255, 255}}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jobs/j1" {
+			fmt.Fprintf(w, `{"data":{"jobId":"j1","status":"VISION_COMPLETED","processedData":{"length":30,"scenes":[
+				{"description":"a","endTs":10,"keyFrameUrl":"%[1]s/kf/0.png"},
+				{"description":"b","startTs":12,"endTs":20,"keyFrameUrl":"%[1]s/kf/1.png"},
+				{"description":"c","endTs":30,"keyFrameUrl":"%[1]s/kf/missing.png"}]}}}`, srv.URL)
+			return
+		}
+		c, ok := frames[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		img := image.NewRGBA(image.Rect(0, 0, 32, 18))
+		for i := 0; i < len(img.Pix); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, c.A
+		}
+		png.Encode(w, img)
+	}))
+	defer srv.Close()
+
+	track, err := New("k", WithBaseURL(srv.URL)).GenerateThumbnailTrack(context.Background(), "j1",
+		&ThumbTrackOptions{TileWidth: 16, TileHeight: 8, Columns: 2, SpriteURL: "thumbs.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVTT := "WEBVTT\n\n" +
+		"00:00:00.000 --> 00:00:10.000\nthumbs.jpg#xywh=0,0,16,8\n\n" +
+		"00:00:12.000 --> 00:00:20.000\nthumbs.jpg#xywh=16,0,16,8\n\n" +
+		"00:00:20.000 --> 00:00:30.000\nthumbs.jpg#xywh=0,8,16,8\n\n"
+	if string(track.VTT) != wantVTT {
+		t.Errorf("VTT =\n%s\nwant:\n%s", track.VTT, wantVTT)
+	}
+	if want := []int{2}; !reflect.DeepEqual(track.Missing, want) {
+		t.Errorf("Missing = %v, want %v", track.Missing, want)
+	}
+
+	sprite, err := jpeg.Decode(bytes.NewReader(track.Sprite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := sprite.Bounds(); b.Dx() != 32 || b.Dy() != 16 {
+		t.Fatalf("sprite is %v, want 2x2 tiles of 16x8", b)
+	}
+	for _, tile := range []struct {
+		x, y    int
+		r, g, b bool // which channels should be bright
+	}{{8, 4, true, false, false}, {24, 4, false, false, true}, {8, 12, false, false, false}} {
+		r, g, b, _ := sprite.At(tile.x, tile.y).RGBA()
+		if bright := func(v uint32) bool { return v > 0x8000 }; bright(r) != tile.r || bright(g) != tile.g || bright(b) != tile.b {
+			t.Errorf("pixel (%d, %d) = %v", tile.x, tile.y, sprite.At(tile.x, tile.y))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package framequery
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TransportProfile is a preset for connection pooling, passed to WithTransportProfile.
+type TransportProfile string
+
+const (
+	// ProfileLowLatency keeps a few warm HTTP/2 connections around for a long
+	// time and caches TLS sessions, so interactive calls skip handshakes.
+	ProfileLowLatency TransportProfile = "low-latency"
+	// ProfileBulk allows many parallel connections per host for batch uploads
+	// and high-volume polling.
+	ProfileBulk TransportProfile = "bulk"
+)
+
+// WithTransportProfile replaces the HTTP transport with one tuned for p.
+// Unknown profiles leave the transport unchanged. A later WithHTTPClient
+// replaces the whole client, profile included.
+func WithTransportProfile(p TransportProfile) Option {
+	return func(c *Client) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ForceAttemptHTTP2 = true
+		t.TLSClientConfig = &tls.Config{ClientSessionCache: tls.NewLRUClientSessionCache(64)}
+		switch p {
+		case ProfileLowLatency:
+			t.MaxIdleConns = 16
+			t.MaxIdleConnsPerHost = 8
+			t.IdleConnTimeout = 10 * time.Minute
+		case ProfileBulk:
+			t.MaxIdleConns = 256
+			t.MaxIdleConnsPerHost = 64
+			t.IdleConnTimeout = 90 * time.Second
+		default:
+			return
+		}
+		hc := *c.httpClient
+		hc.Transport = t
+		c.httpClient = &hc
+	}
+}
+
+// Warmup opens a connection to the API (DNS, TCP, TLS) so the next call reuses
+// it. Only network failures are reported; any HTTP status counts as warm.
+func (c *Client) Warmup(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("framequery: create request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgentHeader())
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("framequery: warmup: %w", err)
+	}
+	// Drain so the connection returns to the idle pool
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return nil
+}
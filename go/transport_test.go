@@ -0,0 +1,90 @@
+package framequery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// profileClient returns a client using profile p that trusts srv's
+// certificate.
+func profileClient(tb testing.TB, srv *httptest.Server, p TransportProfile) (*Client, *http.Transport) {
+	tb.Helper()
+	c := New("k", WithBaseURL(srv.URL), WithTransportProfile(p))
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		tb.Fatalf("transport is %T, want *http.Transport", c.httpClient.Transport)
+	}
+	tr.TLSClientConfig.RootCAs = srv.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	return c, tr
+}
+
+// newQuotaServer is a TLS server answering every request with a quota. It
+// counts the connections it accepts in *conns, if conns is non-nil.
+func newQuotaServer(conns *int) *httptest.Server {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"plan":"pro"}}`))
+	}))
+	if conns != nil {
+		srv.Config.ConnState = func(_ net.Conn, s http.ConnState) {
+			if s == http.StateNew {
+				*conns++
+			}
+		}
+	}
+	srv.StartTLS()
+	return srv
+}
+
+func TestWarmupReusesConnection(t *testing.T) {
+	conns := 0
+	srv := newQuotaServer(&conns)
+	defer srv.Close()
+	c, tr := profileClient(t, srv, ProfileLowLatency)
+	defer tr.CloseIdleConnections()
+	ctx := context.Background()
+
+	if err := c.Warmup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetQuota(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if conns != 1 {
+		t.Errorf("%d connections, want the warm one reused", conns)
+	}
+}
+
+// BenchmarkFirstCall measures a new client's first API call over TLS, with
+// and without Warmup beforehand.
+func BenchmarkFirstCall(b *testing.B) {
+	srv := newQuotaServer(nil)
+	defer srv.Close()
+	ctx := context.Background()
+	for _, warm := range []bool{false, true} {
+		name := "cold"
+		if warm {
+			name = "warm"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				c, tr := profileClient(b, srv, ProfileLowLatency)
+				if warm {
+					if err := c.Warmup(ctx); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.StartTimer()
+				if _, err := c.GetQuota(ctx); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				tr.CloseIdleConnections()
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultUploadFieldName = "file"
+
+// UnsupportedUploadMethodError is returned by Upload when the API asks for an
+// upload method this SDK version does not implement.
+type UnsupportedUploadMethodError struct {
+	Method string
+}
+
+func (e *UnsupportedUploadMethodError) Error() string {
+	return fmt.Sprintf("framequery: unsupported upload method %q", e.Method)
+}
+
+// newUploadRequest builds the request that sends src to the signed upload URL,
+// using the method the create-job response asked for. An empty method means
+// PUT. size is src's length in bytes, or 0 if unknown.
+func newUploadRequest(ctx context.Context, resp *createJobResponse, src io.Reader, size int64, filename string) (*http.Request, error) {
+	switch {
+	case isPutUpload(resp.UploadMethod):
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, resp.UploadURL, src)
+		if err != nil {
+			return nil, err
+		}
+		if size > 0 {
+			// Signed URLs generally reject chunked transfer encoding
+			req.ContentLength = size
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		return req, nil
+	case strings.EqualFold(resp.UploadMethod, http.MethodPost):
+		return newMultipartUploadRequest(ctx, resp, src, filename)
+	default:
+		return nil, &UnsupportedUploadMethodError{Method: resp.UploadMethod}
+	}
+}
+
+// isPutUpload reports whether method, from a create-job response, means a
+// single PUT.
+func isPutUpload(method string) bool {
+	m := strings.ToUpper(method)
+	return m == "" || m == http.MethodPut
+}
+
+// newMultipartUploadRequest streams src as a multipart form, preceded by any
+// form fields from the response (e.g. a presigned POST policy).
+func newMultipartUploadRequest(ctx context.Context, resp *createJobResponse, src io.Reader, filename string) (*http.Request, error) {
+	fieldName := resp.UploadFieldName
+	if fieldName == "" {
+		fieldName = defaultUploadFieldName
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		keys := make([]string, 0, len(resp.UploadFields))
+		for k := range resp.UploadFields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var err error
+		for _, k := range keys {
+			if err = mw.WriteField(k, resp.UploadFields[k]); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			var part io.Writer
+			if part, err = mw.CreateFormFile(fieldName, filename); err == nil {
+				_, err = io.Copy(part, src)
+			}
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, resp.UploadURL, pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req, nil
+}
+
+const (
+	defaultChunkSize        = 500 << 20
+	defaultChunkConcurrency = 1
+)
+
+type uploadPart struct {
+	PartNumber int    `json:"partNumber"`
+	UploadURL  string `json:"uploadUrl,omitempty"`
+	ETag       string `json:"etag,omitempty"`
+}
+
+type uploadPartsResponse struct {
+	Parts []uploadPart `json:"parts"`
+}
+
+// errMultipartUnavailable is returned by uploadMultipart when the API has no
+// upload-parts endpoint; the caller falls back to a single PUT.
+var errMultipartUnavailable = errors.New("framequery: multipart upload unavailable")
+
+// uploadMultipart sends f in chunkSize parts. It asks the API for one signed
+// URL per part (POST /jobs/{id}/upload-parts), PUTs each part, retrying a
+// failed part on its own, then completes the upload with the parts' ETags
+// (POST /jobs/{id}/upload-complete). A 404 for upload-parts returns
+// errMultipartUnavailable before anything is sent.
+func (c *Client) uploadMultipart(ctx context.Context, jobID string, f io.ReaderAt, size, chunkSize int64, concurrency int, events *eventWriter) error {
+	partCount := int((size + chunkSize - 1) / chunkSize)
+	base := "/jobs/" + url.PathEscape(jobID)
+	var parts uploadPartsResponse
+	if err := c.doJSON(ctx, http.MethodPost, base+"/upload-parts", map[string]any{"partCount": partCount, "partSize": chunkSize}, &parts); err != nil {
+		if hasStatus(err, http.StatusNotFound) {
+			return errMultipartUnavailable
+		}
+		return err
+	}
+	if len(parts.Parts) != partCount {
+		return fmt.Errorf("framequery: expected %d upload parts, got %d", partCount, len(parts.Parts))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		sent     int64
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := range parts.Parts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			off := int64(i) * chunkSize
+			n := chunkSize
+			if off+n > size {
+				n = size - off
+			}
+			etag, err := c.putPart(ctx, parts.Parts[i].UploadURL, io.NewSectionReader(f, off, n), n)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("framequery: upload part %d: %w", parts.Parts[i].PartNumber, err)
+					cancel()
+				}
+				return
+			}
+			parts.Parts[i].ETag = etag
+			parts.Parts[i].UploadURL = ""
+			sent += n
+			events.emit(ProgressEvent{Type: EventUploadProgress, JobID: jobID, BytesSent: sent, BytesTotal: size})
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+
+	_, err := c.doRaw(ctx, http.MethodPost, base+"/upload-complete", map[string]any{"parts": parts.Parts})
+	return err
+}
+
+// putPart uploads one part, retrying up to maxRetries times. Returns the ETag.
+func (c *Client) putPart(ctx context.Context, uploadURL string, part *io.SectionReader, n int64) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(c.backoff(attempt - 1)):
+			}
+			part.Seek(0, io.SeekStart)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, part)
+		if err != nil {
+			return "", err
+		}
+		req.ContentLength = n
+		req.Header.Set("Content-Type", "application/octet-stream")
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp.Header.Get("ETag"), nil
+		}
+		lastErr = fmt.Errorf("%s: %s", resp.Status, string(b))
+		if resp.StatusCode < 500 && resp.StatusCode != 429 {
+			break
+		}
+	}
+	return "", lastErr
+}
+
+// newUploadSource returns a reader over all of f and its size. Regular files
+// are read by offset, so multipart parts can be retried and f's position
+// doesn't matter; anything else is returned as-is with size -1, and gets
+// spooled by sendFile.
+func newUploadSource(f fs.File) (io.Reader, int64) {
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() {
+		return f, -1
+	}
+	if ra, ok := f.(io.ReaderAt); ok {
+		return io.NewSectionReader(ra, 0, fi.Size()), fi.Size()
+	}
+	return f, fi.Size()
+}
+
+// spool copies r to a temporary file, for sources that can't be re-read or
+// whose size is unknown. The caller closes and removes the file.
+func spool(r io.Reader) (*os.File, int64, error) {
+	tmp, err := os.CreateTemp("", "framequery-upload-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("framequery: spool upload: %w", err)
+	}
+	n, err := io.Copy(tmp, r)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, 0, fmt.Errorf("framequery: spool upload: %w", err)
+	}
+	return tmp, n, nil
+}
@@ -0,0 +1,365 @@
+package framequery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// uploadServer fakes job creation, single-PUT storage, and the multipart
+// part endpoints. uploadMethod, uploadFields, and fieldName are echoed in
+// the create response, and a POST to the upload URL is parsed as a form. Part failParts[n] fails that many times with 500 before
+// succeeding.
+type uploadServer struct {
+	*httptest.Server
+	advertise bool // create response sets multipartUpload
+	noParts   bool // upload-parts returns 404
+
+	uploadMethod string
+	uploadFields map[string]string
+	fieldName    string
+
+	mu        sync.Mutex
+	failParts map[int]int
+	created   map[string]any    // body of the job-creation POST
+	put       []byte            // body of the single PUT
+	form      map[string]string // fields of the form POST
+	formFile  string            // "field/filename: content" of the form POST's file
+	parts     map[int][]byte    // part number to body
+	attempts  map[int]int
+	completed []uploadPart
+	partCalls int
+}
+
+func newUploadServer(t *testing.T) *uploadServer {
+	s := &uploadServer{parts: map[int][]byte{}, attempts: map[int]int{}, failParts: map[int]int{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/jobs":
+			json.NewDecoder(r.Body).Decode(&s.created)
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"jobId":           "j",
+				"uploadUrl":       s.URL + "/put",
+				"multipartUpload": s.advertise,
+				"uploadMethod":    s.uploadMethod,
+				"uploadFields":    s.uploadFields,
+				"uploadFieldName": s.fieldName,
+			}})
+		case r.URL.Path == "/jobs/j/upload-parts":
+			s.partCalls++
+			if s.noParts {
+				http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+				return
+			}
+			var req struct {
+				PartCount int `json:"partCount"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			var parts []uploadPart
+			for n := 1; n <= req.PartCount; n++ {
+				parts = append(parts, uploadPart{PartNumber: n, UploadURL: fmt.Sprintf("%s/part/%d", s.URL, n)})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": uploadPartsResponse{Parts: parts}})
+		case r.URL.Path == "/jobs/j/upload-complete":
+			var req struct {
+				Parts []uploadPart `json:"parts"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			s.completed = req.Parts
+			w.Write([]byte(`{"data":{}}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/put":
+			s.put, _ = io.ReadAll(r.Body)
+		case r.Method == http.MethodPost && r.URL.Path == "/put":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.form = map[string]string{}
+			for k, v := range r.MultipartForm.Value {
+				s.form[k] = v[0]
+			}
+			for field, fhs := range r.MultipartForm.File {
+				f, _ := fhs[0].Open()
+				b, _ := io.ReadAll(f)
+				f.Close()
+				s.formFile = field + "/" + fhs[0].Filename + ": " + string(b)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/part/"):
+			var n int
+			fmt.Sscanf(r.URL.Path, "/part/%d", &n)
+			b, _ := io.ReadAll(r.Body)
+			s.attempts[n]++
+			if s.failParts[n] > 0 {
+				s.failParts[n]--
+				http.Error(w, "try again", http.StatusInternalServerError)
+				return
+			}
+			if s.failParts[n] < 0 {
+				http.Error(w, "denied", http.StatusForbidden)
+				return
+			}
+			s.parts[n] = b
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	return s
+}
+
+// reassembled joins the received parts in part-number order.
+func (s *uploadServer) reassembled() []byte {
+	var out []byte
+	for n := 1; n <= len(s.parts); n++ {
+		out = append(out, s.parts[n]...)
+	}
+	return out
+}
+
+func writeUploadFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const uploadContent = "0123456789abcdefghij" // 20 bytes: parts of 8, 8, 4
+
+func TestUploadMultipartRetryAndReassembly(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	srv.advertise = true
+	srv.failParts[2] = 1 // the middle part fails once
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(2))
+
+	_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{ChunkSize: 8, ChunkConcurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(srv.reassembled()); got != uploadContent {
+		t.Errorf("reassembled %q, want %q", got, uploadContent)
+	}
+	if want := map[int]int{1: 1, 2: 2, 3: 1}; !reflect.DeepEqual(srv.attempts, want) {
+		t.Errorf("attempts per part = %v, want %v", srv.attempts, want)
+	}
+	want := []uploadPart{{PartNumber: 1, ETag: `"etag-1"`}, {PartNumber: 2, ETag: `"etag-2"`}, {PartNumber: 3, ETag: `"etag-3"`}}
+	if !reflect.DeepEqual(srv.completed, want) {
+		t.Errorf("upload-complete parts = %+v, want %+v", srv.completed, want)
+	}
+	if srv.put != nil {
+		t.Error("single PUT sent as well as parts")
+	}
+}
+
+func TestUploadMultipartPartFails(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	srv.advertise = true
+	srv.failParts[3] = -1 // rejected, not retried
+	c := New("k", WithBaseURL(srv.URL), WithMaxRetries(2))
+
+	_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{ChunkSize: 8})
+	if err == nil || !strings.Contains(err.Error(), "upload part 3") {
+		t.Fatalf("err = %v, want a part 3 failure", err)
+	}
+	if srv.attempts[3] != 1 || srv.completed != nil {
+		t.Errorf("attempts %v, completed %+v: want one attempt and no completion", srv.attempts, srv.completed)
+	}
+}
+
+func TestUploadSinglePutUnlessMultipart(t *testing.T) {
+	tests := []struct {
+		name      string
+		advertise bool
+		noParts   bool
+		force     bool
+		partCalls int
+	}{
+		{"not advertised", false, false, false, 0},
+		{"forced, endpoint missing", false, true, true, 1},
+		{"advertised, endpoint missing", true, true, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newUploadServer(t)
+			defer srv.Close()
+			srv.advertise, srv.noParts = tt.advertise, tt.noParts
+			c := New("k", WithBaseURL(srv.URL))
+
+			_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{ChunkSize: 8, UseMultipart: tt.force})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(srv.put) != uploadContent || len(srv.parts) != 0 {
+				t.Errorf("PUT %q, parts %v; want the whole file in one PUT", srv.put, srv.parts)
+			}
+			if srv.partCalls != tt.partCalls {
+				t.Errorf("upload-parts called %d times, want %d", srv.partCalls, tt.partCalls)
+			}
+		})
+	}
+}
+
+func TestUploadExtraFields(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	var hooked map[string]any
+	c := New("k", WithBaseURL(srv.URL), WithCreateJobHook(func(_ context.Context, path string, body map[string]any) error {
+		if path != "/jobs" {
+			t.Errorf("hook path = %q", path)
+		}
+		hooked = maps.Clone(body)
+		return nil
+	}))
+
+	_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{
+		Filename:    "real.mp4",
+		CallbackURL: "https://example.com/cb",
+		ExtraFields: map[string]any{
+			"fileName":    "other.mp4",
+			"callbackUrl": "https://evil.example.com",
+			"priority":    "high",
+			"routing":     map[string]any{"region": "eu", "weights": []any{1.0, 2.5}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"fileName":    "real.mp4",
+		"callbackUrl": "https://example.com/cb",
+		"priority":    "high",
+		"routing":     map[string]any{"region": "eu", "weights": []any{1.0, 2.5}},
+	}
+	if !reflect.DeepEqual(srv.created, want) {
+		t.Errorf("sent %v, want %v", srv.created, want)
+	}
+	if !reflect.DeepEqual(hooked, want) {
+		t.Errorf("hook saw %v, want %v", hooked, want)
+	}
+}
+
+func TestUploadFormPost(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		want      string
+	}{
+		{"default field", "", "file/clip.mp4: " + uploadContent},
+		{"named field", "video", "video/clip.mp4: " + uploadContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newUploadServer(t)
+			defer srv.Close()
+			srv.uploadMethod = "post"
+			srv.fieldName = tt.fieldName
+			srv.uploadFields = map[string]string{"key": "uploads/j", "policy": "p0l1cy"}
+			c := New("k", WithBaseURL(srv.URL))
+
+			if _, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), nil); err != nil {
+				t.Fatal(err)
+			}
+			if srv.put != nil {
+				t.Errorf("PUT %q; want a form POST", srv.put)
+			}
+			if !reflect.DeepEqual(srv.form, srv.uploadFields) {
+				t.Errorf("form fields = %v, want %v", srv.form, srv.uploadFields)
+			}
+			if srv.formFile != tt.want {
+				t.Errorf("form file = %q, want %q", srv.formFile, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadUnsupportedMethod(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	srv.uploadMethod = "PATCH"
+	c := New("k", WithBaseURL(srv.URL))
+
+	_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), nil)
+	var uerr *UnsupportedUploadMethodError
+	if !errors.As(err, &uerr) || uerr.Method != "PATCH" {
+		t.Fatalf("err = %v, want *UnsupportedUploadMethodError for PATCH", err)
+	}
+	if srv.put != nil || srv.form != nil {
+		t.Errorf("sent PUT %q, form %v; want nothing uploaded", srv.put, srv.form)
+	}
+}
+
+func TestUploadTagsAndMetadata(t *testing.T) {
+	srv := newUploadServer(t)
+	defer srv.Close()
+	c := New("k", WithBaseURL(srv.URL))
+
+	_, err := c.Upload(context.Background(), writeUploadFile(t, uploadContent), &UploadOptions{
+		Tags:     []string{"conf"},
+		Metadata: map[string]string{"team": "ingest"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(srv.created["tags"], []any{"conf"}) || !reflect.DeepEqual(srv.created["metadata"], map[string]any{"team": "ingest"}) {
+		t.Errorf("sent %v, want the tags and metadata", srv.created)
+	}
+}
+
+func TestUploadFS(t *testing.T) {
+	fsys := fstest.MapFS{"clips/talk.mp4": {Data: []byte(uploadContent)}}
+	tests := []struct {
+		name  string
+		opts  *UploadOptions
+		parts int
+	}{
+		{"single PUT", nil, 0},
+		{"multipart", &UploadOptions{ChunkSize: 8, UseMultipart: true}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newUploadServer(t)
+			defer srv.Close()
+			srv.advertise = true
+			c := New("k", WithBaseURL(srv.URL))
+
+			job, err := c.UploadFS(context.Background(), fsys, "clips/talk.mp4", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.ID != "j" || srv.created["fileName"] != "talk.mp4" {
+				t.Errorf("job %q created with %v, want j and fileName talk.mp4", job.ID, srv.created)
+			}
+			got := string(srv.put)
+			if tt.parts > 0 {
+				got = string(srv.reassembled())
+			}
+			if got != uploadContent || len(srv.parts) != tt.parts {
+				t.Errorf("uploaded %q in %d parts, want the file in %d", got, len(srv.parts), tt.parts)
+			}
+		})
+	}
+
+	c := New("k", WithBaseURL("http://127.0.0.1:0"))
+	if _, err := c.UploadFS(context.Background(), fsys, "clips/missing.mp4", nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want fs.ErrNotExist", err)
+	}
+}
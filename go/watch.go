@@ -0,0 +1,108 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Subscription is a running Watch. Updates delivers job states as they are
+// polled; it always holds only the newest one, so a slow reader skips stale
+// states rather than holding up polling.
+type Subscription struct {
+	updates chan *Job
+	done    chan struct{}
+	cancel  context.CancelFunc
+	stopped bool // set under mu by Stop
+	mu      sync.Mutex
+	err     error
+}
+
+// Watch polls jobID in the background, the way WaitForJob does, and sends each
+// polled state on the subscription's Updates channel. Polling ends when the job
+// finishes or fails, when ctx is done, or when Stop is called. Either way the
+// last job state seen is sent once more before Updates is closed, so the final
+// value read is always the latest known state.
+//
+// opts is used as for WaitForJob; its OnProgress callback still runs on each
+// poll.
+func (c *Client) Watch(ctx context.Context, jobID string, opts *ProcessOptions) *Subscription {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription{
+		updates: make(chan *Job, 1),
+		done:    make(chan struct{}),
+		cancel:  cancel,
+	}
+	var o ProcessOptions
+	if opts != nil {
+		o = *opts
+	}
+	var last *Job
+	onProgress := o.OnProgress
+	o.OnProgress = func(j *Job) {
+		last = j
+		s.offer(j)
+		if onProgress != nil {
+			onProgress(j)
+		}
+	}
+
+	go func() {
+		defer close(s.done)
+		defer cancel()
+		_, err := c.waitForJob(ctx, jobID, &o, o.JustCreated)
+		s.mu.Lock()
+		if s.stopped && errors.Is(err, context.Canceled) {
+			err = ErrSubscriptionStopped
+		}
+		s.err = err
+		s.mu.Unlock()
+		if last != nil {
+			s.offer(last)
+		}
+		close(s.updates)
+	}()
+	return s
+}
+
+// offer replaces any update the reader hasn't taken yet with j. Only the
+// polling goroutine sends, so this never blocks.
+func (s *Subscription) offer(j *Job) {
+	select {
+	case <-s.updates:
+	default:
+	}
+	s.updates <- j
+}
+
+// Updates returns the channel of job states. It is closed exactly once, after
+// the final update, when the subscription ends.
+func (s *Subscription) Updates() <-chan *Job {
+	return s.updates
+}
+
+// Stop ends the subscription and waits for its polling goroutine to exit.
+// It is safe to call more than once and from several goroutines, but not from
+// an OnProgress callback of the same subscription.
+func (s *Subscription) Stop() {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.cancel()
+	<-s.done
+}
+
+// Done returns a channel that is closed once the subscription has ended and
+// Updates is closed.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns nil while the subscription is running or if the job completed.
+// Otherwise it returns the error that ended it, as WaitForJob would have, or
+// ErrSubscriptionStopped after Stop.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
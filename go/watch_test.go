@@ -0,0 +1,114 @@
+package framequery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newWatchServer serves job "run", which never finishes, and job "done",
+// which completes on its fourth poll.
+func newWatchServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusVideoProcessing
+		if r.URL.Path == "/jobs/done" && polls.Add(1) > 3 {
+			status = StatusVisionCompleted
+		}
+		w.Write([]byte(`{"data":{"jobId":"x","status":"` + string(status) + `","processedData":{"length":1}}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// checkNoLeak fails if goroutines started after base are still running once
+// idle connections are closed.
+func checkNoLeak(t *testing.T, c *Client, base int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.httpClient.CloseIdleConnections()
+		n := runtime.NumGoroutine()
+		if n <= base {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines: %d before, %d after", base, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func drain(s *Subscription) []*Job {
+	var jobs []*Job
+	for j := range s.Updates() {
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+func TestWatch(t *testing.T) {
+	opts := &ProcessOptions{PollInterval: 10 * time.Millisecond}
+
+	t.Run("stop", func(t *testing.T) {
+		c := New("k", WithBaseURL(newWatchServer(t).URL))
+		base := runtime.NumGoroutine()
+		s := c.Watch(context.Background(), "run", opts)
+		if j := <-s.Updates(); j == nil || j.Status != StatusVideoProcessing {
+			t.Fatalf("first update = %+v", j)
+		}
+		s.Stop()
+		s.Stop() // idempotent
+		select {
+		case <-s.Done():
+		default:
+			t.Fatal("Done not closed after Stop")
+		}
+		if jobs := drain(s); len(jobs) != 1 || jobs[0].Status != StatusVideoProcessing {
+			t.Fatalf("final updates = %v, want the last known state", jobs)
+		}
+		if err := s.Err(); !errors.Is(err, ErrSubscriptionStopped) {
+			t.Fatalf("Err = %v, want ErrSubscriptionStopped", err)
+		}
+		checkNoLeak(t, c, base)
+	})
+
+	t.Run("context cancel", func(t *testing.T) {
+		c := New("k", WithBaseURL(newWatchServer(t).URL))
+		base := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		s := c.Watch(ctx, "run", opts)
+		<-s.Updates()
+		cancel()
+		<-s.Done()
+		if jobs := drain(s); len(jobs) != 1 {
+			t.Fatalf("got %d final updates, want 1", len(jobs))
+		}
+		if err := s.Err(); !errors.Is(err, context.Canceled) || errors.Is(err, ErrSubscriptionStopped) {
+			t.Fatalf("Err = %v, want context.Canceled", err)
+		}
+		s.Stop() // after the end, still safe
+		checkNoLeak(t, c, base)
+	})
+
+	t.Run("terminal", func(t *testing.T) {
+		c := New("k", WithBaseURL(newWatchServer(t).URL))
+		base := runtime.NumGoroutine()
+		s := c.Watch(context.Background(), "done", opts)
+		jobs := drain(s)
+		if len(jobs) == 0 || jobs[len(jobs)-1].Status != StatusVisionCompleted {
+			t.Fatalf("updates = %v, want last one %s", jobs, StatusVisionCompleted)
+		}
+		<-s.Done()
+		if err := s.Err(); err != nil {
+			t.Fatalf("Err = %v, want nil", err)
+		}
+		checkNoLeak(t, c, base)
+	})
+}